@@ -0,0 +1,274 @@
+package guacamole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// Attribute schema object types
+const (
+	attributeSchemaUser            = "user"
+	attributeSchemaUserGroup       = "userGroup"
+	attributeSchemaConnection      = "connection"
+	attributeSchemaConnectionGroup = "connectionGroup"
+)
+
+func extraAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Description: "Additional attributes such as those contributed by guacamole extensions, keyed by attribute name",
+		Optional:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// attributesSchema returns the typed attributes block of a resource.  An attributes block missing
+// from the configuration is planned against the attributes read from guacamole, removing them
+// unless they are all unset or default values
+func attributesSchema(description string, fields map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeList,
+		Description:      description,
+		Optional:         true,
+		MaxItems:         1,
+		DiffSuppressFunc: suppressDefaultSectionDiff("attributes", fields),
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
+
+func dataSourceExtraAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Description: "Attributes not covered by the attributes block, keyed by attribute name",
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func userObjectPath(username string) string {
+	return fmt.Sprintf("users/%s", url.PathEscape(username))
+}
+
+func userGroupObjectPath(identifier string) string {
+	return fmt.Sprintf("userGroups/%s", url.PathEscape(identifier))
+}
+
+func connectionObjectPath(identifier string) string {
+	return fmt.Sprintf("connections/%s", url.PathEscape(identifier))
+}
+
+func connectionGroupObjectPath(identifier string) string {
+	return fmt.Sprintf("connectionGroups/%s", url.PathEscape(identifier))
+}
+
+// typedAttributeKeys returns the attribute names covered by a typed attribute struct
+func typedAttributeKeys(attributes interface{}) []string {
+	var keys []string
+	t := reflect.TypeOf(attributes)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// ReadObjectAttributes gets the raw attribute map of a user, user group, connection or connection group
+func (c *guacamoleClient) ReadObjectAttributes(path string) (map[string]*string, error) {
+	var ret struct {
+		Attributes map[string]*string `json:"attributes"`
+	}
+	err := c.call(http.MethodGet, c.dataSourceURL(path), nil, &ret)
+	return ret.Attributes, err
+}
+
+// WriteObject sends an object to guacamole with its attribute map replaced by attributes,
+// decoding the response into result if it is not nil
+func (c *guacamoleClient) WriteObject(method string, path string, object interface{}, attributes map[string]interface{}, result interface{}) error {
+	body, err := objectBody(object, attributes)
+	if err != nil {
+		return err
+	}
+
+	return c.call(method, c.dataSourceURL(path), body, result)
+}
+
+// objectBody returns the json body of an object with its attribute map replaced by attributes
+func objectBody(object interface{}, attributes map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var body map[string]interface{}
+	err = json.Unmarshal(raw, &body)
+	if err != nil {
+		return nil, err
+	}
+	body["attributes"] = attributes
+
+	return body, nil
+}
+
+// buildObjectAttributes merges the typed attributes and the extra_attributes of a resource into the
+// attribute map sent to guacamole.  When updating, attributes managed outside of terraform (such as
+// those maintained by extensions) are kept by starting from the attributes currently in guacamole.
+func buildObjectAttributes(d *schema.ResourceData, client *guacamoleClient, path string, typed interface{}) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{})
+
+	if path != "" {
+		current, err := client.ReadObjectAttributes(path)
+		if err != nil {
+			return attributes, err
+		}
+		for k, v := range current {
+			if v == nil {
+				attributes[k] = nil
+			} else {
+				attributes[k] = *v
+			}
+		}
+	}
+
+	// Typed attributes always replace the current values, omitted typed attributes are cleared
+	raw, err := json.Marshal(typed)
+	if err != nil {
+		return attributes, err
+	}
+	var typedValues map[string]interface{}
+	err = json.Unmarshal(raw, &typedValues)
+	if err != nil {
+		return attributes, err
+	}
+	for _, k := range typedAttributeKeys(typed) {
+		attributes[k] = typedValues[k]
+	}
+
+	old, new := d.GetChange("extra_attributes")
+	for k := range old.(map[string]interface{}) {
+		if _, ok := new.(map[string]interface{})[k]; !ok {
+			attributes[k] = nil
+		}
+	}
+	for k, v := range new.(map[string]interface{}) {
+		attributes[k] = v
+	}
+
+	return attributes, nil
+}
+
+// extraAttributesCustomizeDiff validates extra_attributes at plan time
+func extraAttributesCustomizeDiff(objectType string, typed interface{}) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if !d.NewValueKnown("extra_attributes") {
+			return nil
+		}
+		client, _ := m.(*guacamoleClient)
+		return validateExtraAttributes(d, client, objectType, typed)
+	}
+}
+
+// validateExtraAttributes checks extra_attributes don't overlap the typed attributes and, when a
+// client is configured and the server publishes an attribute schema, that every attribute is known
+// to guacamole
+func validateExtraAttributes(d parameterGetter, client *guacamoleClient, objectType string, typed interface{}) error {
+	extra := d.Get("extra_attributes").(map[string]interface{})
+	if len(extra) == 0 {
+		return nil
+	}
+
+	var keys []string
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	typedKeys := typedAttributeKeys(typed)
+	var managed []string
+	for _, k := range keys {
+		if stringSliceContains(typedKeys, k) {
+			managed = append(managed, k)
+		}
+	}
+	if len(managed) > 0 {
+		return fmt.Errorf("extra_attributes %s are managed by the attributes block", strings.Join(managed, ", "))
+	}
+
+	if client == nil {
+		return nil
+	}
+	forms, err := client.GetAttributeSchema(objectType)
+	if err != nil {
+		log.Printf("[WARN] unable to read %s attribute schema, skipping extra_attributes validation: %s", objectType, err)
+		return nil
+	}
+	var known []string
+	for _, form := range forms {
+		for _, field := range form.Fields {
+			known = append(known, field.Name)
+		}
+	}
+	var unknown []string
+	for _, k := range keys {
+		if !stringSliceContains(known, k) {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("extra_attributes %s are not part of the guacamole %s attribute schema.  Valid attributes are: %s", strings.Join(unknown, ", "), objectType, strings.Join(known, ", "))
+	}
+
+	return nil
+}
+
+// setExtraAttributes reads the raw attributes of an object into extra_attributes.  Resources only
+// track the attributes they already manage while data sources expose every attribute not
+// covered by the typed attributes.
+func setExtraAttributes(d *schema.ResourceData, client *guacamoleClient, path string, typed interface{}, all bool) error {
+	current, err := client.ReadObjectAttributes(path)
+	if err != nil {
+		return err
+	}
+
+	tracked := d.Get("extra_attributes").(map[string]interface{})
+	typedKeys := typedAttributeKeys(typed)
+
+	extra := make(map[string]string)
+	for k, v := range current {
+		if stringSliceContains(typedKeys, k) {
+			continue
+		}
+		if _, ok := tracked[k]; !ok && !all {
+			continue
+		}
+		if v != nil {
+			extra[k] = *v
+		}
+	}
+
+	return d.Set("extra_attributes", extra)
+}
+
+// typed attribute structs of each object type
+var (
+	typedUserAttributes            = types.GuacUserAttributes{}
+	typedUserGroupAttributes       = types.GuacUserGroupAttributes{}
+	typedConnectionAttributes      = types.GuacConnectionAttributes{}
+	typedConnectionGroupAttributes = types.GuacConnectionGroupAttributes{}
+)
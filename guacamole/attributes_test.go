@@ -0,0 +1,128 @@
+package guacamole
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+func TestTypedAttributeKeys(t *testing.T) {
+	keys := typedAttributeKeys(types.GuacConnectionGroupAttributes{})
+	expected := []string{"max-connections", "max-connections-per-user", "enable-session-affinity"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+}
+
+func TestBuildObjectAttributes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleUser().Schema, map[string]interface{}{
+		"username": "attributes",
+		"extra_attributes": map[string]interface{}{
+			"guac-organization": "Example",
+		},
+	})
+
+	attributes, err := buildObjectAttributes(d, nil, "", types.GuacUserAttributes{
+		GuacFullName: "Test User",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error building attributes: %s", err)
+	}
+
+	if attributes["guac-full-name"] != "Test User" {
+		t.Fatalf("expected typed attribute to be included, got %v", attributes["guac-full-name"])
+	}
+	if v, ok := attributes["guac-email-address"]; !ok || v != nil {
+		t.Fatalf("expected omitted typed attribute to be cleared, got %v", v)
+	}
+	if attributes["guac-organization"] != "Example" {
+		t.Fatalf("expected extra attribute to be included, got %v", attributes["guac-organization"])
+	}
+}
+
+func TestExtraAttributesFakePlan(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+	f.installTOTP()
+
+	cases := []struct {
+		resource *schema.Resource
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			resource: guacamoleUser(),
+			config: map[string]interface{}{
+				"username":         "fakeUser",
+				"extra_attributes": map[string]interface{}{"guac-full-name": "Fake User"},
+			},
+			expected: "extra_attributes guac-full-name are managed by the attributes block",
+		},
+		{
+			resource: guacamoleUser(),
+			config: map[string]interface{}{
+				"username":         "fakeUser",
+				"extra_attributes": map[string]interface{}{"guac-organization": "Example"},
+			},
+			expected: "extra_attributes guac-organization are not part of the guacamole user attribute schema",
+		},
+		{
+			resource: guacamoleConnectionGroup(),
+			config: map[string]interface{}{
+				"name":              "fakeGroup",
+				"parent_identifier": "ROOT",
+				"extra_attributes":  map[string]interface{}{"weight": "1"},
+			},
+			expected: "extra_attributes weight are not part of the guacamole connectionGroup attribute schema",
+		},
+	}
+	for _, c := range cases {
+		_, err := c.resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), client)
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("expected plan to fail with %q, got %v", c.expected, err)
+		}
+	}
+
+	// without a configured provider only the overlap with the typed attributes is checked
+	for _, c := range cases {
+		_, err := c.resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+		managed := strings.Contains(c.expected, "are managed by the attributes block")
+		if managed && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("expected plan without a client to fail with %q, got %v", c.expected, err)
+		}
+		if !managed && err != nil {
+			t.Errorf("expected plan without a client to skip the attribute schema, got %v", err)
+		}
+	}
+
+	if f.requestCount(http.MethodPost, "users") != 0 || f.requestCount(http.MethodPost, "connectionGroups") != 0 {
+		t.Fatalf("expected invalid extra_attributes to be rejected before anything is created")
+	}
+
+	config := map[string]interface{}{
+		"username":         "fakeUser",
+		"extra_attributes": map[string]interface{}{totpKeyConfirmedAttribute: "true"},
+	}
+	state := testFakeApply(t, guacamoleUser(), nil, config, client)
+
+	// imported resources only track the extra attributes listed in the configuration
+	d := guacamoleUser().Data(&terraform.InstanceState{ID: "fakeUser"})
+	imported, err := guacamoleUser().Importer.StateContext(context.Background(), d, client)
+	if err != nil {
+		t.Fatalf("unable to import: %s", err)
+	}
+	refreshed, diags := guacamoleUser().RefreshWithoutUpgrade(context.Background(), imported[0].State(), client)
+	if diags.HasError() {
+		t.Fatalf("unable to refresh: %v", diags)
+	}
+	if n := refreshed.Attributes["extra_attributes.%"]; n != "" && n != "0" {
+		t.Fatalf("expected no extra_attributes after import, got %v", refreshed.Attributes)
+	}
+
+	testFakeDestroy(t, guacamoleUser(), state, client)
+}
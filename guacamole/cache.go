@@ -0,0 +1,434 @@
+package guacamole
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// responseCache holds api responses that are expensive to fetch, such as the connection tree and
+// user listings, for the lifetime of the provider.  Any write through the client invalidates
+// every cached response.  Concurrent reads of a missing response share a single request
+type responseCache struct {
+	disabled bool
+
+	mu      sync.Mutex
+	entries map[string]*responseCacheEntry
+}
+
+type responseCacheEntry struct {
+	ready chan struct{}
+	value interface{}
+	err   error
+}
+
+// get returns the cached response of a key, fetching it if it isn't cached.  Failed requests
+// aren't cached
+func (c *responseCache) get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if c.disabled {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*responseCacheEntry)
+	}
+	entry, ok := c.entries[key]
+	if ok {
+		c.mu.Unlock()
+		<-entry.ready
+		return entry.value, entry.err
+	}
+	entry = &responseCacheEntry{ready: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.value, entry.err = fetch()
+	close(entry.ready)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	return entry.value, entry.err
+}
+
+// invalidate drops every cached response.  Requests in flight complete for their callers but
+// their responses aren't kept
+func (c *responseCache) invalidate() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// connectionPathTree is the connection tree of ROOT along with the paths of its connections and
+// connection groups keyed by identifier
+type connectionPathTree struct {
+	root  types.GuacConnectionGroup
+	paths types.GuacConnectionGroupPathTree
+}
+
+// connectionPathTree gets the cached connection tree of ROOT.  Paths are built the way the api
+// client builds them
+func (c *guacamoleClient) connectionPathTree() (*connectionPathTree, error) {
+	value, err := c.cache.get("connectionGroups/ROOT/tree", func() (interface{}, error) {
+		c.sessionLock.RLock()
+		defer c.sessionLock.RUnlock()
+		root, err := c.Client.GetConnectionTree("ROOT")
+		if err != nil {
+			return nil, err
+		}
+		tree := &connectionPathTree{
+			root: root,
+			paths: types.GuacConnectionGroupPathTree{
+				Connections: make(map[string]string),
+				Groups:      make(map[string]string),
+			},
+		}
+		addConnectionPaths(root, &tree.paths)
+		return tree, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*connectionPathTree), nil
+}
+
+// addConnectionPaths adds the paths of the descendants of a connection group to a path tree
+func addConnectionPaths(nested types.GuacConnectionGroup, paths *types.GuacConnectionGroupPathTree) {
+	for _, group := range nested.ChildGroups {
+		if nested.Path != "" {
+			group.Path = fmt.Sprintf("%s/%s", nested.Path, group.Name)
+		} else {
+			group.Path = group.Name
+		}
+		paths.Groups[group.Identifier] = group.Path
+		addConnectionPaths(group, paths)
+	}
+
+	for _, connection := range nested.ChildConnections {
+		if nested.Name == "ROOT" {
+			paths.Connections[connection.Identifier] = connection.Name
+		} else {
+			paths.Connections[connection.Identifier] = fmt.Sprintf("%s/%s", nested.Path, connection.Name)
+		}
+	}
+}
+
+// findConnectionGroup returns a connection group of a tree by identifier
+func findConnectionGroup(group types.GuacConnectionGroup, identifier string) (types.GuacConnectionGroup, bool) {
+	if group.Identifier == identifier {
+		return group, true
+	}
+	for _, child := range group.ChildGroups {
+		if found, ok := findConnectionGroup(child, identifier); ok {
+			return found, true
+		}
+	}
+	return types.GuacConnectionGroup{}, false
+}
+
+// GetConnectionTree gets the connection tree of a connection group, taken from the cached tree
+// of ROOT when it holds the group.  The returned tree is shared and must not be modified
+func (c *guacamoleClient) GetConnectionTree(identifier string) (types.GuacConnectionGroup, error) {
+	if c.cache.disabled {
+		c.sessionLock.RLock()
+		defer c.sessionLock.RUnlock()
+		return c.Client.GetConnectionTree(identifier)
+	}
+
+	tree, err := c.connectionPathTree()
+	if err != nil {
+		return types.GuacConnectionGroup{}, err
+	}
+	if group, ok := findConnectionGroup(tree.root, identifier); ok {
+		return group, nil
+	}
+
+	// groups outside the tree of ROOT are fetched on their own
+	value, err := c.cache.get(fmt.Sprintf("connectionGroups/%s/tree", identifier), func() (interface{}, error) {
+		c.sessionLock.RLock()
+		defer c.sessionLock.RUnlock()
+		return c.Client.GetConnectionTree(identifier)
+	})
+	if err != nil {
+		return types.GuacConnectionGroup{}, err
+	}
+	return value.(types.GuacConnectionGroup), nil
+}
+
+// ReadConnectionGroup gets a connection group by identifier along with its direct children
+func (c *guacamoleClient) ReadConnectionGroup(identifier string) (types.GuacConnectionGroup, error) {
+	var ret types.GuacConnectionGroup
+	err := c.call(http.MethodGet, c.dataSourceURL(connectionGroupObjectPath(identifier)), nil, &ret)
+	if err != nil {
+		return ret, err
+	}
+
+	tree, err := c.GetConnectionTree(identifier)
+	if err != nil {
+		return ret, err
+	}
+
+	for _, group := range tree.ChildGroups {
+		ret.ChildGroups = append(ret.ChildGroups, types.GuacConnectionGroup{
+			Name:              group.Name,
+			Identifier:        group.Identifier,
+			ParentIdentifier:  group.ParentIdentifier,
+			Type:              group.Type,
+			ActiveConnections: group.ActiveConnections,
+		})
+	}
+	ret.ChildConnections = append(ret.ChildConnections, tree.ChildConnections...)
+
+	return ret, nil
+}
+
+// ReadConnectionByPath gets a connection by path (Parent/Name)
+func (c *guacamoleClient) ReadConnectionByPath(path string) (types.GuacConnection, error) {
+	tree, err := c.connectionPathTree()
+	if err != nil {
+		return types.GuacConnection{}, err
+	}
+
+	for identifier, p := range tree.paths.Connections {
+		if p == path {
+			connection, err := c.ReadConnection(identifier)
+			connection.Path = path
+			return connection, err
+		}
+	}
+
+	return types.GuacConnection{}, fmt.Errorf("no connection found with path: %s", path)
+}
+
+// ReadConnectionGroupByPath gets a connection group by path (Parent/Name)
+func (c *guacamoleClient) ReadConnectionGroupByPath(path string) (types.GuacConnectionGroup, error) {
+	tree, err := c.connectionPathTree()
+	if err != nil {
+		return types.GuacConnectionGroup{}, err
+	}
+
+	for identifier, p := range tree.paths.Groups {
+		if p == path {
+			group, err := c.ReadConnectionGroup(identifier)
+			group.Path = path
+			return group, err
+		}
+	}
+
+	return types.GuacConnectionGroup{}, fmt.Errorf("no connection group found with path: %s", path)
+}
+
+// GetConnectionPathById gets a connection path by identifier
+func (c *guacamoleClient) GetConnectionPathById(identifier string) (string, error) {
+	tree, err := c.connectionPathTree()
+	if err != nil {
+		return "", err
+	}
+	return tree.paths.Connections[identifier], nil
+}
+
+// GetConnectionGroupPathById gets a connection group path by identifier
+func (c *guacamoleClient) GetConnectionGroupPathById(identifier string) (string, error) {
+	tree, err := c.connectionPathTree()
+	if err != nil {
+		return "", err
+	}
+	return tree.paths.Groups[identifier], nil
+}
+
+// ListUsers lists all users
+func (c *guacamoleClient) ListUsers() ([]types.GuacUser, error) {
+	value, err := c.cache.get("users", func() (interface{}, error) {
+		c.sessionLock.RLock()
+		defer c.sessionLock.RUnlock()
+		return c.Client.ListUsers()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.GuacUser(nil), value.([]types.GuacUser)...), nil
+}
+
+// ListUserGroups lists all user groups
+func (c *guacamoleClient) ListUserGroups() ([]types.GuacUserGroup, error) {
+	value, err := c.cache.get("userGroups", func() (interface{}, error) {
+		c.sessionLock.RLock()
+		defer c.sessionLock.RUnlock()
+		return c.Client.ListUserGroups()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]types.GuacUserGroup(nil), value.([]types.GuacUserGroup)...), nil
+}
+
+// The api client write methods below invalidate the cache once guacamole has been updated.  Writes
+// made with call invalidate it as well
+
+// CreateConnection creates a guacamole connection
+func (c *guacamoleClient) CreateConnection(connection *types.GuacConnection) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.CreateConnection(connection)
+}
+
+// UpdateConnection updates a connection by identifier
+func (c *guacamoleClient) UpdateConnection(connection *types.GuacConnection) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.UpdateConnection(connection)
+}
+
+// DeleteConnection deletes a connection by identifier
+func (c *guacamoleClient) DeleteConnection(identifier string) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.DeleteConnection(identifier)
+}
+
+// CreateConnectionGroup creates a guacamole connection group
+func (c *guacamoleClient) CreateConnectionGroup(group *types.GuacConnectionGroup) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.CreateConnectionGroup(group)
+}
+
+// UpdateConnectionGroup updates a connection group by identifier
+func (c *guacamoleClient) UpdateConnectionGroup(group *types.GuacConnectionGroup) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.UpdateConnectionGroup(group)
+}
+
+// DeleteConnectionGroup deletes a connection group by identifier
+func (c *guacamoleClient) DeleteConnectionGroup(identifier string) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.DeleteConnectionGroup(identifier)
+}
+
+// CreateUser creates a guacamole user
+func (c *guacamoleClient) CreateUser(user *types.GuacUser) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.CreateUser(user)
+}
+
+// UpdateUser updates a user by username
+func (c *guacamoleClient) UpdateUser(user *types.GuacUser) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.UpdateUser(user)
+}
+
+// DeleteUser deletes a user by username
+func (c *guacamoleClient) DeleteUser(username string) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.DeleteUser(username)
+}
+
+// SetUserConnectionPermissions patches the connection permissions of a user
+func (c *guacamoleClient) SetUserConnectionPermissions(username string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserConnectionPermissions(username, permissionItems)
+}
+
+// SetUserGroupMembership patches the user groups a user is a member of
+func (c *guacamoleClient) SetUserGroupMembership(username string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupMembership(username, permissionItems)
+}
+
+// SetUserPermissions patches the permissions of a user
+func (c *guacamoleClient) SetUserPermissions(username string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserPermissions(username, permissionItems)
+}
+
+// CreateUserGroup creates a guacamole user group
+func (c *guacamoleClient) CreateUserGroup(group *types.GuacUserGroup) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.CreateUserGroup(group)
+}
+
+// UpdateUserGroup updates a user group by identifier
+func (c *guacamoleClient) UpdateUserGroup(group *types.GuacUserGroup) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.UpdateUserGroup(group)
+}
+
+// DeleteUserGroup deletes a user group by identifier
+func (c *guacamoleClient) DeleteUserGroup(identifier string) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.DeleteUserGroup(identifier)
+}
+
+// SetUserGroupConnectionPermissions patches the connection permissions of a user group
+func (c *guacamoleClient) SetUserGroupConnectionPermissions(group string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupConnectionPermissions(group, permissionItems)
+}
+
+// SetUserGroupParentGroups patches the user groups a user group is a member of
+func (c *guacamoleClient) SetUserGroupParentGroups(group string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupParentGroups(group, permissionItems)
+}
+
+// SetUserGroupMemberGroups patches the member user groups of a user group
+func (c *guacamoleClient) SetUserGroupMemberGroups(group string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupMemberGroups(group, permissionItems)
+}
+
+// SetUserGroupPermissions patches the permissions of a user group
+func (c *guacamoleClient) SetUserGroupPermissions(group string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupPermissions(group, permissionItems)
+}
+
+// SetUserGroupUsers patches the member users of a user group
+func (c *guacamoleClient) SetUserGroupUsers(group string, permissionItems *[]types.GuacPermissionItem) error {
+	defer c.cache.invalidate()
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.SetUserGroupUsers(group, permissionItems)
+}
@@ -0,0 +1,106 @@
+package guacamole
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+func TestResponseCacheConnectionTree(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	group := types.GuacConnectionGroup{Name: "group", ParentIdentifier: "ROOT", Type: "ORGANIZATIONAL"}
+	if err := client.CreateConnectionGroup(&group); err != nil {
+		t.Fatalf("unable to create connection group: %s", err)
+	}
+	connection := types.GuacConnection{Name: "connection", ParentIdentifier: group.Identifier, Protocol: "ssh"}
+	if err := client.WriteConnection(http.MethodPost, "connections", &connection, nil, nil, &connection); err != nil {
+		t.Fatalf("unable to create connection: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		read, err := client.ReadConnectionByPath("group/connection")
+		if err != nil || read.Identifier != connection.Identifier {
+			t.Fatalf("expected connection %s, got %s: %v", connection.Identifier, read.Identifier, err)
+		}
+		g, err := client.ReadConnectionGroupByPath("group")
+		if err != nil || g.Identifier != group.Identifier || len(g.ChildConnections) != 1 {
+			t.Fatalf("expected connection group %s with a connection, got %#v: %v", group.Identifier, g, err)
+		}
+		if path, _ := client.GetConnectionPathById(connection.Identifier); path != "group/connection" {
+			t.Fatalf("expected connection path group/connection, got %s", path)
+		}
+		if path, _ := client.GetConnectionGroupPathById(group.Identifier); path != "group" {
+			t.Fatalf("expected connection group path group, got %s", path)
+		}
+	}
+	if n := f.requestCount(http.MethodGet, "connectionGroups/ROOT/tree"); n != 1 {
+		t.Errorf("expected the tree of ROOT to be fetched once, got %d", n)
+	}
+	if n := f.requestCount(http.MethodGet, "connectionGroups/"+group.Identifier+"/tree"); n != 0 {
+		t.Errorf("expected the tree of %s to be taken from the tree of ROOT, got %d requests", group.Identifier, n)
+	}
+
+	// writes invalidate the cached tree
+	if err := client.DeleteConnection(connection.Identifier); err != nil {
+		t.Fatalf("unable to delete connection: %s", err)
+	}
+	if _, err := client.ReadConnectionByPath("group/connection"); err == nil {
+		t.Errorf("expected deleted connection to be missing from the tree")
+	}
+	if n := f.requestCount(http.MethodGet, "connectionGroups/ROOT/tree"); n != 2 {
+		t.Errorf("expected the tree of ROOT to be fetched again after a write, got %d", n)
+	}
+}
+
+func TestResponseCacheListings(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ListUserGroups(); err != nil {
+				t.Errorf("unable to list user groups: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := f.requestCount(http.MethodGet, "userGroups"); n != 1 {
+		t.Errorf("expected concurrent listings to share a request, got %d", n)
+	}
+
+	if err := client.WriteObject(http.MethodPost, "userGroups", &types.GuacUserGroup{Identifier: "group"}, nil, nil); err != nil {
+		t.Fatalf("unable to create user group: %s", err)
+	}
+	groups, err := client.ListUserGroups()
+	if err != nil || len(groups) != 1 || groups[0].Identifier != "group" {
+		t.Fatalf("expected listing to hold the new user group, got %v: %v", groups, err)
+	}
+	if n := f.requestCount(http.MethodGet, "userGroups"); n != 2 {
+		t.Errorf("expected user groups to be listed again after a write, got %d", n)
+	}
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+	client.cache.disabled = true
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListUsers(); err != nil {
+			t.Fatalf("unable to list users: %s", err)
+		}
+		if _, err := client.GetConnectionTree("ROOT"); err != nil {
+			t.Fatalf("unable to get connection tree: %s", err)
+		}
+	}
+	if n := f.requestCount(http.MethodGet, "users"); n != 2 {
+		t.Errorf("expected users to be listed on every call, got %d", n)
+	}
+	if n := f.requestCount(http.MethodGet, "connectionGroups/ROOT/tree"); n != 2 {
+		t.Errorf("expected the tree of ROOT to be fetched on every call, got %d", n)
+	}
+}
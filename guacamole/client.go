@@ -0,0 +1,398 @@
+package guacamole
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	guac "github.com/techBeck03/guacamole-api-client"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+const (
+	tokenPath       = "api/tokens"
+	sessionDataPath = "api/session/data"
+)
+
+// guacamoleClient wraps the guacamole api client with the details of the
+// authenticated session that the api client does not expose
+type guacamoleClient struct {
+	*guac.Client
+	config  guac.Config
+	session types.AuthenticationResponse
+	http    *http.Client
+
+	// omitDataSourceSecrets blanks secret connection parameters read by data sources
+	omitDataSourceSecrets bool
+
+	// cache holds connection trees, listings and schemas for the lifetime of the provider
+	cache responseCache
+
+	// sessionCachePath is the file the session token is kept in between runs, if any
+	sessionCachePath string
+
+	// sessionLock is held by every request for as long as it runs, and exclusively by
+	// reauthenticate while it replaces the api client, password and token of the session
+	sessionLock sync.RWMutex
+}
+
+// newGuacamoleClient authenticates against guacamole and returns a client for the new session
+func newGuacamoleClient(config guac.Config) (*guacamoleClient, error) {
+	c := &guacamoleClient{
+		config: config,
+		http:   http.DefaultClient,
+	}
+	if config.DisableTLSVerification {
+		c.http = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+
+	if config.Token != "" && config.DataSource != "" {
+		// Re-posting an existing token returns its session details without creating a new session
+		session, _, err := c.authenticate(url.Values{"token": {config.Token}})
+		if err != nil {
+			log.Printf("[WARN] unable to look up session details for supplied token: %s", err)
+			session = types.AuthenticationResponse{
+				AuthToken:            config.Token,
+				DataSource:           config.DataSource,
+				AvailableDataSources: []string{config.DataSource},
+			}
+		}
+		c.session = session
+	} else {
+		session, cookies, err := c.authenticate(url.Values{
+			"username": {config.Username},
+			"password": {config.Password},
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.session = session
+
+		// Hand the new session to the api client as token based authentication
+		config.Token = session.AuthToken
+		config.DataSource = session.DataSource
+		if !config.DisableCookies {
+			merged := make(map[string]string)
+			for k, v := range config.Cookies {
+				merged[k] = v
+			}
+			for _, cookie := range cookies {
+				merged[cookie.Name] = cookie.Value
+			}
+			config.Cookies = merged
+		}
+	}
+
+	client := guac.New(config)
+	err := client.Connect()
+	if err != nil {
+		return nil, err
+	}
+	c.Client = &client
+
+	return c, nil
+}
+
+// authenticate posts credentials to the token endpoint and returns the resulting session
+func (c *guacamoleClient) authenticate(values url.Values) (types.AuthenticationResponse, []*http.Cookie, error) {
+	var session types.AuthenticationResponse
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%s", c.config.URL, tokenPath), bytes.NewBufferString(values.Encode()))
+	if err != nil {
+		return session, nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if !c.config.DisableCookies {
+		for k, v := range c.config.Cookies {
+			request.AddCookie(&http.Cookie{Name: k, Value: v})
+		}
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return session, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusForbidden {
+		return session, nil, fmt.Errorf("invalid Credentials")
+	}
+	if !(response.StatusCode >= 200 && response.StatusCode <= 299) {
+		body, _ := io.ReadAll(response.Body)
+		return session, nil, fmt.Errorf("authentication failed with status code %d: %s", response.StatusCode, string(body))
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&session)
+	if err != nil {
+		return session, nil, err
+	}
+
+	return session, response.Cookies(), nil
+}
+
+// dataSourceURL returns the url of a path below the session data source
+func (c *guacamoleClient) dataSourceURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", c.config.URL, sessionDataPath, c.session.DataSource, path)
+}
+
+// call performs an authenticated json request and decodes the response into result
+func (c *guacamoleClient) call(method string, path string, params interface{}, result interface{}) error {
+	if method != http.MethodGet {
+		defer c.cache.invalidate()
+	}
+
+	request, err := c.CreateJSONRequest(method, path, params)
+	if err != nil {
+		return err
+	}
+
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.Call(request, result)
+}
+
+// GetEffectivePermissions gets the effective permissions of the authenticated user
+func (c *guacamoleClient) GetEffectivePermissions() (types.GuacPermissionData, error) {
+	var ret types.GuacPermissionData
+	err := c.call(http.MethodGet, c.dataSourceURL("self/effectivePermissions"), nil, &ret)
+	return ret, err
+}
+
+// ReadSelf gets the authenticated user
+func (c *guacamoleClient) ReadSelf() (types.GuacUser, error) {
+	var ret types.GuacUser
+	err := c.call(http.MethodGet, c.dataSourceURL("self"), nil, &ret)
+	return ret, err
+}
+
+// GetProtocolSchemas gets the connection and sharing profile forms of every protocol
+func (c *guacamoleClient) GetProtocolSchemas() (map[string]types.ProtocolSchema, error) {
+	value, err := c.cache.get("schema/protocols", func() (interface{}, error) {
+		var ret map[string]types.ProtocolSchema
+		err := c.call(http.MethodGet, c.dataSourceURL("schema/protocols"), nil, &ret)
+		return ret, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]types.ProtocolSchema), nil
+}
+
+// apiURL returns the url of a path below the base guacamole api
+func (c *guacamoleClient) apiURL(path string) string {
+	return fmt.Sprintf("%s/api/%s", c.config.URL, path)
+}
+
+// GetLanguages gets the languages available in the guacamole interface keyed by language code
+func (c *guacamoleClient) GetLanguages() (map[string]string, error) {
+	var ret map[string]string
+	err := c.call(http.MethodGet, c.apiURL("languages"), nil, &ret)
+	return ret, err
+}
+
+// GetPatches gets the html patches installed by guacamole extensions
+func (c *guacamoleClient) GetPatches() ([]string, error) {
+	var ret []string
+	err := c.call(http.MethodGet, c.apiURL("patches"), nil, &ret)
+	return ret, err
+}
+
+// GetVersion gets the guacamole version from the english translation of the web application, the
+// REST API doesn't publish it
+func (c *guacamoleClient) GetVersion() (string, error) {
+	var ret struct {
+		App struct {
+			Version string `json:"VERSION"`
+		} `json:"APP"`
+	}
+	err := c.call(http.MethodGet, fmt.Sprintf("%s/translations/en.json", c.config.URL), nil, &ret)
+	return ret.App.Version, err
+}
+
+// GetAttributeSchema gets the attribute forms of an object type (user, userGroup,
+// connection, connectionGroup or sharingProfile)
+func (c *guacamoleClient) GetAttributeSchema(objectType string) ([]types.ConnectionForm, error) {
+	path := fmt.Sprintf("schema/%sAttributes", objectType)
+	value, err := c.cache.get(path, func() (interface{}, error) {
+		var ret []types.ConnectionForm
+		err := c.call(http.MethodGet, c.dataSourceURL(path), nil, &ret)
+		return ret, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]types.ConnectionForm), nil
+}
+
+// redactConnectionSecrets blanks the secret parameters of a connection and of the raw guacd
+// parameters read for it
+func redactConnectionSecrets(connection *types.GuacConnection, parameters map[string]string) {
+	for _, field := range connectionSecretFields(&connection.Parameters) {
+		*field = ""
+	}
+	for _, p := range connectionParameters {
+		if p.sensitive {
+			delete(parameters, p.name)
+		}
+	}
+}
+
+// UpdateSelfPassword changes the password of the authenticated user
+func (c *guacamoleClient) UpdateSelfPassword(username string, oldPassword string, newPassword string) error {
+	params := map[string]string{
+		"oldPassword": oldPassword,
+		"newPassword": newPassword,
+	}
+	return c.call(http.MethodPut, c.dataSourceURL(fmt.Sprintf("users/%s/password", url.PathEscape(username))), params, nil)
+}
+
+// password returns the password the client authenticated with, if any
+func (c *guacamoleClient) password() string {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.config.Password
+}
+
+// reauthenticate replaces the session of a password authenticated client with a session for the
+// new password.  Requests running on the previous session are waited for before it is replaced
+// and removed
+func (c *guacamoleClient) reauthenticate(password string) error {
+	c.sessionLock.RLock()
+	config := c.config
+	c.sessionLock.RUnlock()
+	config.Password = password
+
+	fresh, err := newGuacamoleClient(config)
+	if err != nil {
+		return err
+	}
+
+	c.sessionLock.Lock()
+	previous := c.Client
+	c.Client = fresh.Client
+	c.config.Password = password
+	c.session.AuthToken = fresh.session.AuthToken
+	c.sessionLock.Unlock()
+
+	if c.sessionCachePath != "" {
+		c.cacheSession()
+	}
+
+	err = previous.Disconnect()
+	if err != nil {
+		log.Printf("[WARN] unable to remove previous guacamole session: %s", err)
+	}
+
+	return nil
+}
+
+// The api client methods below hold the session for the requests they make, the methods of the
+// api client wrapped elsewhere hold it as well
+
+// Call performs an authenticated request and decodes the response into result
+func (c *guacamoleClient) Call(request *http.Request, result interface{}) error {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.Call(request, result)
+}
+
+// Disconnect removes the session
+func (c *guacamoleClient) Disconnect() error {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.Disconnect()
+}
+
+// GetProtocolChoices gets the names of the protocols supported by guacamole
+func (c *guacamoleClient) GetProtocolChoices() ([]string, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetProtocolChoices()
+}
+
+// ReadConnection gets a connection by identifier
+func (c *guacamoleClient) ReadConnection(identifier string) (types.GuacConnection, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.ReadConnection(identifier)
+}
+
+// ListConnections gets every connection
+func (c *guacamoleClient) ListConnections() ([]types.GuacConnection, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.ListConnections()
+}
+
+// ListConnectionGroups gets every connection group
+func (c *guacamoleClient) ListConnectionGroups() ([]types.GuacConnectionGroup, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.ListConnectionGroups()
+}
+
+// ReadUser gets a user by username
+func (c *guacamoleClient) ReadUser(username string) (types.GuacUser, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.ReadUser(username)
+}
+
+// GetUserPermissions gets the permissions of a user
+func (c *guacamoleClient) GetUserPermissions(username string) (types.GuacPermissionData, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserPermissions(username)
+}
+
+// GetUserGroupMembership gets the groups a user is a member of
+func (c *guacamoleClient) GetUserGroupMembership(username string) ([]string, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserGroupMembership(username)
+}
+
+// ReadUserGroup gets a user group by name
+func (c *guacamoleClient) ReadUserGroup(name string) (types.GuacUserGroup, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.ReadUserGroup(name)
+}
+
+// GetUserGroupPermissions gets the permissions of a user group
+func (c *guacamoleClient) GetUserGroupPermissions(identifier string) (types.GuacPermissionData, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserGroupPermissions(identifier)
+}
+
+// GetUserGroupParentGroups gets the groups a user group is a member of
+func (c *guacamoleClient) GetUserGroupParentGroups(group string) ([]string, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserGroupParentGroups(group)
+}
+
+// GetUserGroupMemberGroups gets the member groups of a user group
+func (c *guacamoleClient) GetUserGroupMemberGroups(group string) ([]string, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserGroupMemberGroups(group)
+}
+
+// GetUserGroupUsers gets the member users of a user group
+func (c *guacamoleClient) GetUserGroupUsers(group string) ([]string, error) {
+	c.sessionLock.RLock()
+	defer c.sessionLock.RUnlock()
+	return c.Client.GetUserGroupUsers(group)
+}
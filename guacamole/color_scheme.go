@@ -0,0 +1,213 @@
+package guacamole
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// Number of palette entries supported by guacd terminal color schemes
+const colorSchemePaletteSize = 256
+
+// colorSchemeColorRegex matches an X11 rgb color spec or a reference to a palette entry
+var colorSchemeColorRegex = regexp.MustCompile(`^(rgb:[0-9a-fA-F]{2}/[0-9a-fA-F]{2}/[0-9a-fA-F]{2}|color([0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5]))$`)
+
+// customColorSchemeSchema returns the schema of the custom_color_scheme block of text protocol
+// connections
+func customColorSchemeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Description:   "Custom terminal color scheme",
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"parameters.0.color_scheme"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"foreground": {
+					Type:             schema.TypeString,
+					Description:      "Default foreground color",
+					Optional:         true,
+					ValidateDiagFunc: validateColorSchemeColor,
+				},
+				"background": {
+					Type:             schema.TypeString,
+					Description:      "Default background color",
+					Optional:         true,
+					ValidateDiagFunc: validateColorSchemeColor,
+				},
+				"palette": {
+					Type:             schema.TypeMap,
+					Description:      "Palette colors keyed by index (0-255)",
+					Optional:         true,
+					Elem:             &schema.Schema{Type: schema.TypeString},
+					ValidateDiagFunc: validateColorSchemePalette,
+				},
+			},
+		},
+	}
+}
+
+// dataSourceCustomColorSchemeSchema returns the computed custom_color_scheme block of text
+// protocol connection data sources
+func dataSourceCustomColorSchemeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Custom terminal color scheme",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"foreground": {
+					Type:        schema.TypeString,
+					Description: "Default foreground color",
+					Computed:    true,
+				},
+				"background": {
+					Type:        schema.TypeString,
+					Description: "Default background color",
+					Computed:    true,
+				},
+				"palette": {
+					Type:        schema.TypeMap,
+					Description: "Palette colors keyed by index (0-255)",
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func validateColorSchemeColor(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !colorSchemeColorRegex.MatchString(v.(string)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid color",
+			Detail:        fmt.Sprintf("%q is not a valid color, expected `rgb:RR/GG/BB` or a palette reference such as `color7`", v.(string)),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+func validateColorSchemePalette(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for k, color := range v.(map[string]interface{}) {
+		index, err := strconv.Atoi(k)
+		if err != nil || index < 0 || index >= colorSchemePaletteSize || strconv.Itoa(index) != k {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid palette index",
+				Detail:        fmt.Sprintf("%q is not a valid palette index, expected an integer between 0 and %d", k, colorSchemePaletteSize-1),
+				AttributePath: path,
+			})
+			continue
+		}
+		diags = append(diags, validateColorSchemeColor(color, path.IndexString(k))...)
+	}
+
+	return diags
+}
+
+// expandColorScheme returns the guacd color-scheme value of a parameters block, serializing
+// custom_color_scheme when it is set
+func expandColorScheme(parameters map[string]interface{}) string {
+	custom, _ := parameters["custom_color_scheme"].([]interface{})
+	if len(custom) == 0 || custom[0] == nil {
+		return parameters["color_scheme"].(string)
+	}
+	scheme := custom[0].(map[string]interface{})
+
+	var entries []string
+	if v := scheme["foreground"].(string); v != "" {
+		entries = append(entries, "foreground: "+v)
+	}
+	if v := scheme["background"].(string); v != "" {
+		entries = append(entries, "background: "+v)
+	}
+
+	palette := scheme["palette"].(map[string]interface{})
+	var indexes []int
+	for k := range palette {
+		index, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		entries = append(entries, fmt.Sprintf("color%d: %s", index, palette[strconv.Itoa(index)].(string)))
+	}
+
+	return strings.Join(entries, "; ")
+}
+
+// flattenColorScheme splits a guacd color-scheme value into the color_scheme preset and the
+// custom_color_scheme block. Values that are neither a preset nor a parsable custom scheme are
+// kept as color_scheme so they are reported instead of dropped
+func flattenColorScheme(value string) (string, []interface{}) {
+	var parameterInterface types.GuacConnectionParameters
+	if value == "" || !stringInSlice(parameterInterface.ValidColorSchemes(), []string{value}).HasError() {
+		return value, nil
+	}
+
+	scheme, err := parseColorScheme(value)
+	if err != nil {
+		return value, nil
+	}
+
+	return "", []interface{}{scheme}
+}
+
+// parseColorScheme parses guacd's custom color scheme syntax
+// (`foreground: rgb:ff/ff/ff; background: color0; color0: rgb:00/00/00`)
+func parseColorScheme(value string) (map[string]interface{}, error) {
+	scheme := map[string]interface{}{
+		"foreground": "",
+		"background": "",
+	}
+	palette := make(map[string]interface{})
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid color scheme entry %q", entry)
+		}
+		key := strings.TrimSpace(parts[0])
+		color := strings.TrimSpace(parts[1])
+		if !colorSchemeColorRegex.MatchString(color) {
+			return nil, fmt.Errorf("invalid color %q in color scheme entry %q", color, entry)
+		}
+
+		switch {
+		case key == "foreground" || key == "background":
+			scheme[key] = color
+		case strings.HasPrefix(key, "color"):
+			index, err := strconv.Atoi(strings.TrimPrefix(key, "color"))
+			if err != nil || index < 0 || index >= colorSchemePaletteSize {
+				return nil, fmt.Errorf("invalid palette index in color scheme entry %q", entry)
+			}
+			palette[strconv.Itoa(index)] = color
+		default:
+			return nil, fmt.Errorf("unknown color scheme entry %q", entry)
+		}
+	}
+
+	scheme["palette"] = palette
+	return scheme, nil
+}
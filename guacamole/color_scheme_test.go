@@ -0,0 +1,123 @@
+package guacamole
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestFlattenColorScheme(t *testing.T) {
+	cases := map[string]struct {
+		value  string
+		preset string
+		custom []interface{}
+	}{
+		"empty": {},
+		"preset": {
+			value:  "green-black",
+			preset: "green-black",
+		},
+		"custom": {
+			value: "foreground: rgb:ff/ff/ff;background:color0; color7: rgb:AA/bb/cc;",
+			custom: []interface{}{
+				map[string]interface{}{
+					"foreground": "rgb:ff/ff/ff",
+					"background": "color0",
+					"palette": map[string]interface{}{
+						"7": "rgb:AA/bb/cc",
+					},
+				},
+			},
+		},
+		"unparsable": {
+			value:  "foreground: red",
+			preset: "foreground: red",
+		},
+		"unknown entry": {
+			value:  "cursor: rgb:ff/ff/ff",
+			preset: "cursor: rgb:ff/ff/ff",
+		},
+		"palette index out of range": {
+			value:  "color256: rgb:ff/ff/ff",
+			preset: "color256: rgb:ff/ff/ff",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			preset, custom := flattenColorScheme(c.value)
+			if preset != c.preset {
+				t.Fatalf("expected color_scheme %q, got %q", c.preset, preset)
+			}
+			if !reflect.DeepEqual(custom, c.custom) {
+				t.Fatalf("expected custom_color_scheme %#v, got %#v", c.custom, custom)
+			}
+		})
+	}
+}
+
+func TestExpandColorScheme(t *testing.T) {
+	parameters := map[string]interface{}{
+		"color_scheme": "",
+		"custom_color_scheme": []interface{}{
+			map[string]interface{}{
+				"foreground": "",
+				"background": "rgb:00/00/00",
+				"palette": map[string]interface{}{
+					"10": "rgb:0a/0a/0a",
+					"2":  "color10",
+				},
+			},
+		},
+	}
+
+	value := expandColorScheme(parameters)
+	expected := "background: rgb:00/00/00; color2: color10; color10: rgb:0a/0a/0a"
+	if value != expected {
+		t.Fatalf("expected %q, got %q", expected, value)
+	}
+
+	_, custom := flattenColorScheme(value)
+	if !reflect.DeepEqual(custom, parameters["custom_color_scheme"]) {
+		t.Fatalf("expected the color scheme to round trip, got %#v", custom)
+	}
+
+	parameters = map[string]interface{}{
+		"color_scheme":        "white-black",
+		"custom_color_scheme": []interface{}{},
+	}
+	if value := expandColorScheme(parameters); value != "white-black" {
+		t.Fatalf("expected the preset to be used, got %q", value)
+	}
+}
+
+func TestValidateColorSchemePalette(t *testing.T) {
+	cases := map[string]struct {
+		palette map[string]interface{}
+		valid   bool
+	}{
+		"valid": {
+			palette: map[string]interface{}{"0": "rgb:00/00/00", "255": "color0"},
+			valid:   true,
+		},
+		"index out of range": {
+			palette: map[string]interface{}{"256": "rgb:00/00/00"},
+		},
+		"non canonical index": {
+			palette: map[string]interface{}{"07": "rgb:00/00/00"},
+		},
+		"invalid color": {
+			palette: map[string]interface{}{"1": "#ffffff"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			diags := validateColorSchemePalette(c.palette, cty.GetAttrPath("palette"))
+			if diags.HasError() == c.valid {
+				t.Fatalf("expected valid=%t, got %v", c.valid, diags)
+			}
+		})
+	}
+}
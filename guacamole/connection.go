@@ -0,0 +1,553 @@
+package guacamole
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// connectionResource returns the connection resource of a protocol.  Resources of each protocol
+// add their schema version, state upgraders and plan time checks
+func connectionResource(protocol string) *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceConnectionCreate(protocol),
+		ReadContext:   resourceConnectionRead(protocol),
+		UpdateContext: resourceConnectionUpdate(protocol),
+		DeleteContext: resourceConnectionDelete,
+		CustomizeDiff: extraAttributesCustomizeDiff(attributeSchemaConnection, typedConnectionAttributes),
+		Schema:        connectionResourceSchema(protocol),
+		Importer: &schema.ResourceImporter{
+			StateContext: importConnectionState(protocol),
+		},
+	}
+}
+
+// dataSourceConnection returns the connection data source of a protocol
+func dataSourceConnection(protocol string) *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceConnectionRead(protocol),
+		Schema:      dataSourceConnectionSchema(protocol),
+	}
+}
+
+// connectionValidators check the settings of a protocol that depend on each other, protocols
+// without any being checked by validateConnection
+var connectionValidators = map[string]func(d *schema.ResourceData) diag.Diagnostics{
+	"rdp": validateConnectionRDP,
+}
+
+func resourceConnectionRead(protocol string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client := m.(*guacamoleClient)
+
+		// Warning or errors can be collected in a slice type
+		var diags diag.Diagnostics
+
+		identifier := d.Id()
+
+		connection, err := client.ReadConnection(identifier)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		parameters, err := client.ReadConnectionParameters(identifier)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		check := convertGuacConnectionToResourceData(d, &connection, parameters, protocol)
+		if check.HasError() {
+			return check
+		}
+
+		err = setExtraAttributes(d, client, connectionObjectPath(identifier), typedConnectionAttributes, false)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(identifier)
+
+		return diags
+	}
+}
+
+func dataSourceConnectionRead(protocol string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client := m.(*guacamoleClient)
+
+		// Warning or errors can be collected in a slice type
+		var diags diag.Diagnostics
+
+		identifier := d.Get("identifier").(string)
+		path := d.Get("path").(string)
+
+		if path == "" && identifier == "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Missing required parameter",
+				Detail:   "Either `identifier` or `path` must be specified",
+			})
+			return diags
+		}
+
+		if path != "" && identifier != "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Identifier and Path are mutually exclusive",
+				Detail:   "Either `identifier` or `path` must be specified but not both",
+			})
+			return diags
+		}
+
+		// get connection
+		var connection types.GuacConnection
+		if identifier != "" {
+			c, err := client.ReadConnection(identifier)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			connection = c
+		} else {
+			c, err := client.ReadConnectionByPath(path)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			connection = c
+		}
+
+		parameters, err := client.ReadConnectionParameters(connection.Identifier)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if client.omitDataSourceSecrets {
+			redactConnectionSecrets(&connection, parameters)
+		}
+
+		check := convertGuacConnectionToResourceData(d, &connection, parameters, protocol)
+
+		if check.HasError() {
+			return check
+		}
+
+		err = setExtraAttributes(d, client, connectionObjectPath(connection.Identifier), typedConnectionAttributes, true)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(connection.Identifier)
+
+		return diags
+	}
+}
+
+func resourceConnectionCreate(protocol string) schema.CreateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client := m.(*guacamoleClient)
+
+		validate := validateConnectionProtocol(d, protocol)
+
+		if validate.HasError() {
+			return validate
+		}
+
+		connection, parameters, check := convertResourceDataToGuacConnection(d, protocol)
+
+		if check.HasError() {
+			return check
+		}
+
+		attributes, err := buildObjectAttributes(d, client, "", connection.Attributes)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.WriteConnection(http.MethodPost, "connections", &connection, attributes, parameters, &connection)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.Set("identifier", connection.Identifier)
+		d.SetId(connection.Identifier)
+
+		return resourceConnectionRead(protocol)(ctx, d, m)
+	}
+}
+
+func resourceConnectionUpdate(protocol string) schema.UpdateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		client := m.(*guacamoleClient)
+
+		if connectionHasChanges(d, protocol) {
+			validate := validateConnectionProtocol(d, protocol)
+
+			if validate.HasError() {
+				return validate
+			}
+
+			connection, parameters, check := convertResourceDataToGuacConnection(d, protocol)
+
+			if check.HasError() {
+				return check
+			}
+
+			err := resolveConnectionSecrets(d, client, &connection.Parameters, connectionParameterSections(protocol))
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			attributes, err := buildObjectAttributes(d, client, connectionObjectPath(connection.Identifier), connection.Attributes)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			err = client.WriteConnection(http.MethodPut, connectionObjectPath(connection.Identifier), &connection, attributes, parameters, nil)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			d.SetId(connection.Identifier)
+		}
+
+		return resourceConnectionRead(protocol)(ctx, d, m)
+	}
+}
+
+func resourceConnectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err := client.DeleteConnection(d.Id())
+
+	if err != nil {
+		diags = append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// connectionResourceSchema returns the schema of the connection resource of a protocol, with
+// its parameters taken from the parameter registry
+func connectionResourceSchema(protocol string) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the guacamole connection",
+			Required:    true,
+		},
+		"identifier": {
+			Type:        schema.TypeString,
+			Description: "Numeric identifier of the guacamole connection",
+			Computed:    true,
+		},
+		"parent_identifier": {
+			Type:        schema.TypeString,
+			Description: "Parent identifier of the guacamole connection",
+			Optional:    true,
+			Default:     "ROOT",
+		},
+		"protocol": {
+			Type:        schema.TypeString,
+			Description: "Protocol type of the guacamole connection",
+			Computed:    true,
+		},
+		"active_connections": {
+			Type:        schema.TypeInt,
+			Description: "Active connection count for the guacamole connection",
+			Computed:    true,
+		},
+		"secret_state_mode": secretStateModeSchema(),
+		"extra_attributes":  extraAttributesSchema(),
+		"attributes": attributesSchema("Guacamole connection attributes", map[string]*schema.Schema{
+			"guacd_hostname": {
+				Type:             schema.TypeString,
+				Description:      "Guacd proxy hostname",
+				Optional:         true,
+				DiffSuppressFunc: suppressDefaultValueDiff("localhost"),
+			},
+			"guacd_port": {
+				Type:             schema.TypeInt,
+				Description:      "Guacd proxy port",
+				Optional:         true,
+				DiffSuppressFunc: suppressDefaultValueDiff("4822"),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+			},
+			"guacd_encryption": {
+				Type:             schema.TypeString,
+				Description:      "Guacd proxy encryption type",
+				Optional:         true,
+				DiffSuppressFunc: suppressDefaultValueDiff("none"),
+			},
+			"failover_only": {
+				Type:        schema.TypeBool,
+				Description: "Use load balancing for failover only",
+				Optional:    true,
+			},
+			"weight": {
+				Type:             schema.TypeInt,
+				Description:      "Load balancing connection weight",
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"max_connections": {
+				Type:             schema.TypeInt,
+				Description:      "Maximum concurrent total connections",
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"max_connections_per_user": {
+				Type:             schema.TypeInt,
+				Description:      "Maximum concurrent connections per user",
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+		}),
+		"parameters": {
+			Type:        schema.TypeList,
+			Description: "Guacamole connection parameters",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: connectionParametersSchema(protocol, false),
+			},
+		},
+	}
+
+	addParameterSections(s, connectionParameterSections(protocol))
+
+	return s
+}
+
+// dataSourceConnectionSchema returns the schema of the connection data source of a protocol,
+// with its parameters taken from the parameter registry
+func dataSourceConnectionSchema(protocol string) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the guacamole connection",
+			Computed:    true,
+		},
+		"identifier": {
+			Type:        schema.TypeString,
+			Description: "Numeric identifier of the guacamole connection",
+			Optional:    true,
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Description: "Path of connection",
+			Optional:    true,
+		},
+		"parent_identifier": {
+			Type:        schema.TypeString,
+			Description: "Parent identifier of the guacamole connection",
+			Computed:    true,
+		},
+		"protocol": {
+			Type:        schema.TypeString,
+			Description: "Protocol type of the guacamole connection",
+			Computed:    true,
+		},
+		"active_connections": {
+			Type:        schema.TypeInt,
+			Description: "Active connection count for the guacamole connection",
+			Computed:    true,
+		},
+		"extra_attributes": dataSourceExtraAttributesSchema(),
+		"attributes": {
+			Type:        schema.TypeList,
+			Description: "Guacamole connection attributes",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"guacd_hostname": {
+						Type:        schema.TypeString,
+						Description: "Guacd proxy hostname",
+						Computed:    true,
+					},
+					"guacd_port": {
+						Type:        schema.TypeInt,
+						Description: "Guacd proxy port",
+						Computed:    true,
+					},
+					"guacd_encryption": {
+						Type:        schema.TypeString,
+						Description: "Guacd proxy encryption type",
+						Computed:    true,
+					},
+					"failover_only": {
+						Type:        schema.TypeBool,
+						Description: "Use load balancing for failover only",
+						Computed:    true,
+					},
+					"weight": {
+						Type:        schema.TypeInt,
+						Description: "Load balancing connection weight",
+						Computed:    true,
+					},
+					"max_connections": {
+						Type:        schema.TypeInt,
+						Description: "Maximum concurrent total connections",
+						Computed:    true,
+					},
+					"max_connections_per_user": {
+						Type:        schema.TypeInt,
+						Description: "Maximum concurrent connections per user",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"parameters": {
+			Type:        schema.TypeList,
+			Description: "Guacamole connection parameters",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: connectionParametersSchema(protocol, true),
+			},
+		},
+	}
+
+	addParameterSections(s, connectionParameterSections(protocol))
+
+	return s
+}
+
+// convertGuacConnectionToResourceData sets a connection read from guacamole, along with the raw
+// guacd parameters read for it, in a connection resource or data source of its protocol
+func convertGuacConnectionToResourceData(d *schema.ResourceData, connection *types.GuacConnection, guacd map[string]string, protocol string) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	d.Set("name", connection.Name)
+	d.Set("identifier", connection.Identifier)
+	d.Set("parent_identifier", connection.ParentIdentifier)
+	d.Set("protocol", connection.Protocol)
+	d.Set("active_connections", connection.ActiveConnections)
+
+	attributes := map[string]interface{}{
+		"guacd_hostname":           connection.Attributes.GuacdHostname,
+		"guacd_port":               stringToInt(connection.Attributes.GuacdPort),
+		"guacd_encryption":         connection.Attributes.GuacdEncryption,
+		"failover_only":            stringToBool(connection.Attributes.FailoverOnly),
+		"weight":                   stringToInt(connection.Attributes.Weight),
+		"max_connections":          stringToInt(connection.Attributes.MaxConnections),
+		"max_connections_per_user": stringToInt(connection.Attributes.MaxConnectionsPerUser),
+	}
+	var attributeList []map[string]interface{}
+
+	attributeList = append(attributeList, attributes)
+
+	d.Set("attributes", attributeList)
+
+	sections := connectionParameterSections(protocol)
+	parameters := flattenConnectionParameters(protocol, &connection.Parameters, guacd)
+
+	check := hashConnectionSecrets(d, parameters, sections)
+	if check.HasError() {
+		return check
+	}
+
+	err := setConnectionParameters(d, parameters, sections)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// convertResourceDataToGuacConnection builds the guacamole connection of a connection resource,
+// returning the guacd parameters missing from the api client types separately
+func convertResourceDataToGuacConnection(d *schema.ResourceData, protocol string) (types.GuacConnection, map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var connection types.GuacConnection
+
+	connection.Name = d.Get("name").(string)
+	connection.Identifier = d.Get("identifier").(string)
+	connection.ParentIdentifier = d.Get("parent_identifier").(string)
+	connection.Protocol = protocol
+
+	config := d.GetRawConfig()
+	attributeList := d.Get("attributes").([]interface{})
+
+	if len(attributeList) > 0 {
+		attributes := attributeList[0].(map[string]interface{})
+		connection.Attributes = types.GuacConnectionAttributes{
+			GuacdHostname:         attributes["guacd_hostname"].(string),
+			GuacdPort:             intToString(attributes["guacd_port"].(int)),
+			GuacdEncryption:       attributes["guacd_encryption"].(string),
+			FailoverOnly:          boolToString(attributes["failover_only"].(bool)),
+			Weight:                configuredIntToString(attributes["weight"].(int), attributeConfigured(config, "weight")),
+			MaxConnections:        configuredIntToString(attributes["max_connections"].(int), attributeConfigured(config, "max_connections")),
+			MaxConnectionsPerUser: configuredIntToString(attributes["max_connections_per_user"].(int), attributeConfigured(config, "max_connections_per_user")),
+		}
+	}
+
+	values := mergeParameterSections(d, connectionParameterSections(protocol))[0].(map[string]interface{})
+	parameters, guacd := expandConnectionParameters(protocol, values, config)
+	connection.Parameters = parameters
+
+	return connection, guacd, diags
+}
+
+// validateConnection checks the connection attributes, parameters being validated by their
+// schema
+func validateConnection(d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attributeList := d.Get("attributes").([]interface{})
+
+	var attributeInterface types.GuacConnectionAttributes
+	restrictedValueAttributes := map[string][]string{
+		"guacd_encryption": attributeInterface.ValidEncryptionTypes(),
+	}
+
+	if len(attributeList) > 0 {
+		attributes := attributeList[0].(map[string]interface{})
+		// validate restricted value fields
+		for k, v := range restrictedValueAttributes {
+			if attributes[k].(string) != "" {
+				check := stringInSlice(v, []string{attributes[k].(string)})
+				if check.HasError() {
+					diags = append(diags, check...)
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// validateConnectionProtocol checks a connection with the validator of its protocol
+func validateConnectionProtocol(d *schema.ResourceData, protocol string) diag.Diagnostics {
+	if validate, ok := connectionValidators[protocol]; ok {
+		return validate(d)
+	}
+	return validateConnection(d)
+}
+
+// connectionHasChanges returns whether any argument of a connection resource sent to guacamole
+// changed, including its parameter section blocks
+func connectionHasChanges(d *schema.ResourceData, protocol string) bool {
+	keys := []string{"name", "identifier", "parent_identifier", "attributes", "extra_attributes", "parameters"}
+	for _, section := range connectionParameterSections(protocol) {
+		keys = append(keys, section.name)
+	}
+	return d.HasChanges(keys...)
+}
@@ -0,0 +1,434 @@
+package guacamole
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+const (
+	accessTypeConnection      = "connection"
+	accessTypeConnectionGroup = "connection_group"
+)
+
+// accessGrant describes a single permission grant on a connection or connection group
+type accessGrant struct {
+	grantee          string
+	userGroup        string
+	permissions      []string
+	objectType       string
+	objectIdentifier string
+}
+
+func dataSourceConnectionAccess() *schema.Resource {
+	grantSchema := func(grantee string, description string, inherited bool) *schema.Resource {
+		s := map[string]*schema.Schema{
+			grantee: {
+				Type:        schema.TypeString,
+				Description: description,
+				Computed:    true,
+			},
+			"permissions": {
+				Type:        schema.TypeList,
+				Description: "Permissions held on the granting object",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"object_type": {
+				Type:        schema.TypeString,
+				Description: "Type of the object the permissions are granted on",
+				Computed:    true,
+			},
+			"object_identifier": {
+				Type:        schema.TypeString,
+				Description: "Identifier of the object the permissions are granted on",
+				Computed:    true,
+			},
+		}
+		if inherited {
+			s["user_group"] = &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "User group the access is inherited from",
+				Computed:    true,
+			}
+		}
+		return &schema.Resource{Schema: s}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceConnectionAccessRead,
+		Schema: map[string]*schema.Schema{
+			"identifier": {
+				Type:        schema.TypeString,
+				Description: "Numeric identifier of the connection or connection group",
+				Optional:    true,
+				Computed:    true,
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Description: "Path of the connection or connection group",
+				Optional:    true,
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Description:      "Type of object to look up access for",
+				Optional:         true,
+				Default:          accessTypeConnection,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{accessTypeConnection, accessTypeConnectionGroup}, false)),
+			},
+			"parallelism": {
+				Type:             schema.TypeInt,
+				Description:      "Maximum number of concurrent permission lookups",
+				Optional:         true,
+				Default:          4,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 32)),
+			},
+			"users": {
+				Type:        schema.TypeList,
+				Description: "Users granted access directly",
+				Computed:    true,
+				Elem:        grantSchema("username", "Username of the user granted access", false),
+			},
+			"user_groups": {
+				Type:        schema.TypeList,
+				Description: "User groups granted access directly",
+				Computed:    true,
+				Elem:        grantSchema("identifier", "Identifier of the user group granted access", false),
+			},
+			"inherited_users": {
+				Type:        schema.TypeList,
+				Description: "Users inheriting access through user group membership",
+				Computed:    true,
+				Elem:        grantSchema("username", "Username of the user inheriting access", true),
+			},
+		},
+	}
+}
+
+func dataSourceConnectionAccessRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	identifier := d.Get("identifier").(string)
+	path := d.Get("path").(string)
+	objectType := d.Get("type").(string)
+	parallelism := d.Get("parallelism").(int)
+
+	if path == "" && identifier == "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Missing required parameter",
+			Detail:   "Either `identifier` or `path` must be specified",
+		})
+		return diags
+	}
+
+	if path != "" && identifier != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Identifier and Path are mutually exclusive",
+			Detail:   "Either `identifier` or `path` must be specified but not both",
+		})
+		return diags
+	}
+
+	// objects granting access to the target, keyed by object type
+	targets := map[string][]string{}
+
+	if objectType == accessTypeConnection {
+		var connection types.GuacConnection
+		var err error
+		if identifier != "" {
+			connection, err = client.ReadConnection(identifier)
+		} else {
+			connection, err = client.ReadConnectionByPath(path)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		identifier = connection.Identifier
+		targets[accessTypeConnection] = []string{identifier}
+
+		// balancing groups hand out their child connections, so access to a
+		// balancing group holding this connection is access to this connection
+		ancestors, err := balancingAncestors(client, connection.ParentIdentifier)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(ancestors) > 0 {
+			targets[accessTypeConnectionGroup] = ancestors
+		}
+	} else {
+		var group types.GuacConnectionGroup
+		var err error
+		if identifier != "" {
+			group, err = client.ReadConnectionGroup(identifier)
+		} else {
+			group, err = client.ReadConnectionGroupByPath(path)
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		identifier = group.Identifier
+		targets[accessTypeConnectionGroup] = []string{identifier}
+
+		// balancing groups also hand out their child balancing groups
+		if group.Type == "BALANCING" {
+			ancestors, err := balancingAncestors(client, group.ParentIdentifier)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			targets[accessTypeConnectionGroup] = append(targets[accessTypeConnectionGroup], ancestors...)
+		}
+	}
+
+	users, err := client.ListUsers()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	userGroups, err := client.ListUserGroups()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Scan user permissions
+	var mutex sync.Mutex
+	var userGrants []accessGrant
+	err = runParallel(parallelism, len(users), func(i int) error {
+		permissions, err := client.GetUserPermissions(users[i].Username)
+		if err != nil {
+			return err
+		}
+		grants := matchAccessGrants(users[i].Username, &permissions, targets)
+		mutex.Lock()
+		userGrants = append(userGrants, grants...)
+		mutex.Unlock()
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Scan user group permissions
+	var groupGrants []accessGrant
+	err = runParallel(parallelism, len(userGroups), func(i int) error {
+		permissions, err := client.GetUserGroupPermissions(userGroups[i].Identifier)
+		if err != nil {
+			return err
+		}
+		grants := matchAccessGrants(userGroups[i].Identifier, &permissions, targets)
+		mutex.Lock()
+		groupGrants = append(groupGrants, grants...)
+		mutex.Unlock()
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	inheritedGrants, err := resolveInheritedGrants(client, parallelism, groupGrants)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("identifier", identifier)
+	d.Set("users", flattenAccessGrants(userGrants, "username"))
+	d.Set("user_groups", flattenAccessGrants(groupGrants, "identifier"))
+	d.Set("inherited_users", flattenAccessGrants(inheritedGrants, "username"))
+
+	d.SetId(fmt.Sprintf("%s/%s", objectType, identifier))
+
+	return diags
+}
+
+// balancingAncestors returns the identifiers of the BALANCING groups a connection or balancing
+// group is reached through, walking up from its parent until the first group that isn't balancing
+func balancingAncestors(client *guacamoleClient, parentIdentifier string) ([]string, error) {
+	var identifiers []string
+	for parentIdentifier != "" && parentIdentifier != "ROOT" && !stringSliceContains(identifiers, parentIdentifier) {
+		parent, err := client.ReadConnectionGroup(parentIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Type != "BALANCING" {
+			break
+		}
+		identifiers = append(identifiers, parent.Identifier)
+		parentIdentifier = parent.ParentIdentifier
+	}
+	return identifiers, nil
+}
+
+// matchAccessGrants returns the grants in a permission set that apply to the target objects
+func matchAccessGrants(grantee string, permissions *types.GuacPermissionData, targets map[string][]string) []accessGrant {
+	var grants []accessGrant
+
+	permissionSets := map[string]map[string][]string{
+		accessTypeConnection:      permissions.ConnectionPermissions,
+		accessTypeConnectionGroup: permissions.ConnectionGroupPermissions,
+	}
+
+	for objectType, identifiers := range targets {
+		for _, identifier := range identifiers {
+			granted, ok := permissionSets[objectType][identifier]
+			if !ok || len(granted) == 0 {
+				continue
+			}
+			sorted := append([]string{}, granted...)
+			sort.Strings(sorted)
+			grants = append(grants, accessGrant{
+				grantee:          grantee,
+				permissions:      sorted,
+				objectType:       objectType,
+				objectIdentifier: identifier,
+			})
+		}
+	}
+	return grants
+}
+
+// resolveInheritedGrants expands user group grants to the member users of each group,
+// including the members of nested member groups
+func resolveInheritedGrants(client *guacamoleClient, parallelism int, groupGrants []accessGrant) ([]accessGrant, error) {
+	var mutex sync.Mutex
+	var inherited []accessGrant
+
+	// cache member lookups since the same group may hold several grants
+	memberUsers := map[string][]string{}
+	memberGroups := map[string][]string{}
+
+	pending := []string{}
+	for _, grant := range groupGrants {
+		pending = append(pending, grant.grantee)
+	}
+
+	for len(pending) > 0 {
+		var lookup []string
+		for _, group := range pending {
+			if _, ok := memberUsers[group]; !ok && !stringSliceContains(lookup, group) {
+				lookup = append(lookup, group)
+			}
+		}
+		pending = nil
+
+		err := runParallel(parallelism, len(lookup), func(i int) error {
+			users, err := client.GetUserGroupUsers(lookup[i])
+			if err != nil {
+				return err
+			}
+			groups, err := client.GetUserGroupMemberGroups(lookup[i])
+			if err != nil {
+				return err
+			}
+			mutex.Lock()
+			memberUsers[lookup[i]] = users
+			memberGroups[lookup[i]] = groups
+			mutex.Unlock()
+			return nil
+		})
+		if err != nil {
+			return inherited, err
+		}
+
+		for _, group := range lookup {
+			pending = append(pending, memberGroups[group]...)
+		}
+	}
+
+	for _, grant := range groupGrants {
+		seen := map[string]bool{}
+		queue := []string{grant.grantee}
+		for len(queue) > 0 {
+			group := queue[0]
+			queue = queue[1:]
+			if seen[group] {
+				continue
+			}
+			seen[group] = true
+			for _, username := range memberUsers[group] {
+				inherited = append(inherited, accessGrant{
+					grantee:          username,
+					userGroup:        group,
+					permissions:      grant.permissions,
+					objectType:       grant.objectType,
+					objectIdentifier: grant.objectIdentifier,
+				})
+			}
+			queue = append(queue, memberGroups[group]...)
+		}
+	}
+
+	return inherited, nil
+}
+
+func flattenAccessGrants(grants []accessGrant, granteeKey string) []interface{} {
+	sort.Slice(grants, func(i, j int) bool {
+		a := []string{grants[i].grantee, grants[i].userGroup, grants[i].objectType, grants[i].objectIdentifier}
+		b := []string{grants[j].grantee, grants[j].userGroup, grants[j].objectType, grants[j].objectIdentifier}
+		return strings.Join(a, "\x00") < strings.Join(b, "\x00")
+	})
+
+	var flattened []interface{}
+	for _, grant := range grants {
+		entry := map[string]interface{}{
+			granteeKey:          grant.grantee,
+			"permissions":       grant.permissions,
+			"object_type":       grant.objectType,
+			"object_identifier": grant.objectIdentifier,
+		}
+		if grant.userGroup != "" {
+			entry["user_group"] = grant.userGroup
+		}
+		flattened = append(flattened, entry)
+	}
+	return flattened
+}
+
+// runParallel calls fn for every index in [0, count) using at most limit goroutines,
+// returning the first error encountered
+func runParallel(limit int, count int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	semaphore := make(chan struct{}, limit)
+
+	for i := 0; i < count; i++ {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if err := fn(i); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func stringSliceContains(slice []string, value string) bool {
+	for _, s := range slice {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package guacamole
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDataSourceConnectionAccessFake(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	// web is balanced by pool, itself balanced by region inside the organizational group prod
+	prod := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "prod",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+	region := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "region",
+		"parent_identifier": prod.ID,
+		"type":              "BALANCING",
+	}, client)
+	pool := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "pool",
+		"parent_identifier": region.ID,
+		"type":              "BALANCING",
+	}, client)
+	web := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name":              "web",
+		"parent_identifier": pool.ID,
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "web.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "web",
+			},
+		},
+	}, client)
+
+	// alice reaches the connection through team, a member group of ops, which is granted the
+	// balancing group holding the connection
+	testFakeApply(t, guacamoleUserGroup(), nil, map[string]interface{}{
+		"identifier":        "ops",
+		"connection_groups": []interface{}{pool.ID},
+	}, client)
+	testFakeApply(t, guacamoleUserGroup(), nil, map[string]interface{}{
+		"identifier": "team",
+	}, client)
+	f.mu.Lock()
+	f.userGroups["ops"].memberGroups["team"] = true
+	f.mu.Unlock()
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username":         "alice",
+		"group_membership": []interface{}{"team"},
+	}, client)
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username":    "bob",
+		"connections": []interface{}{web.ID},
+	}, client)
+	// carol reaches the connection through region, while the organizational group of dave hands
+	// out nothing
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username":          "carol",
+		"connection_groups": []interface{}{region.ID},
+	}, client)
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username":          "dave",
+		"connection_groups": []interface{}{prod.ID},
+	}, client)
+
+	r := dataSourceConnectionAccess()
+	d := r.TestResourceData()
+	d.Set("path", "prod/region/pool/web")
+	d.Set("type", accessTypeConnection)
+	d.Set("parallelism", 2)
+	diags := r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read connection access: %v", diags)
+	}
+
+	if d.Get("identifier").(string) != web.ID {
+		t.Fatalf("expected path to resolve to connection %s, got %q", web.ID, d.Get("identifier"))
+	}
+
+	// guacadmin holds ADMINISTER but no explicit grant, so it isn't listed
+	expected := map[string][]interface{}{
+		"users": {
+			map[string]interface{}{
+				"username":          "bob",
+				"permissions":       []interface{}{"READ"},
+				"object_type":       accessTypeConnection,
+				"object_identifier": web.ID,
+			},
+			map[string]interface{}{
+				"username":          "carol",
+				"permissions":       []interface{}{"READ"},
+				"object_type":       accessTypeConnectionGroup,
+				"object_identifier": region.ID,
+			},
+		},
+		"user_groups": {
+			map[string]interface{}{
+				"identifier":        "ops",
+				"permissions":       []interface{}{"READ"},
+				"object_type":       accessTypeConnectionGroup,
+				"object_identifier": pool.ID,
+			},
+		},
+		"inherited_users": {
+			map[string]interface{}{
+				"username":          "alice",
+				"user_group":        "team",
+				"permissions":       []interface{}{"READ"},
+				"object_type":       accessTypeConnectionGroup,
+				"object_identifier": pool.ID,
+			},
+		},
+	}
+	for k, v := range expected {
+		if actual := d.Get(k).([]interface{}); !reflect.DeepEqual(actual, v) {
+			t.Errorf("expected %s %v, got %v", k, v, actual)
+		}
+	}
+
+	// the balancing group itself is reached through ops and the balancing group above it
+	d = r.TestResourceData()
+	d.Set("identifier", pool.ID)
+	d.Set("type", accessTypeConnectionGroup)
+	d.Set("parallelism", 2)
+	diags = r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read connection group access: %v", diags)
+	}
+	if users := d.Get("users").([]interface{}); !reflect.DeepEqual(users, expected["users"][1:]) {
+		t.Errorf("expected users %v, got %v", expected["users"][1:], users)
+	}
+	if groups := d.Get("user_groups").([]interface{}); !reflect.DeepEqual(groups, expected["user_groups"]) {
+		t.Errorf("expected user_groups %v, got %v", expected["user_groups"], groups)
+	}
+	if inherited := d.Get("inherited_users").([]interface{}); !reflect.DeepEqual(inherited, expected["inherited_users"]) {
+		t.Errorf("expected inherited_users %v, got %v", expected["inherited_users"], inherited)
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
 	"github.com/techBeck03/guacamole-api-client/types"
 )
 
@@ -44,6 +43,7 @@ func dataSourceConnectionGroup() *schema.Resource {
 				Description: "Identifier of guacamole connection group",
 				Computed:    true,
 			},
+			"extra_attributes": dataSourceExtraAttributesSchema(),
 			"attributes": {
 				Type:        schema.TypeList,
 				Description: "Attributes of guacamole connection group",
@@ -51,12 +51,12 @@ func dataSourceConnectionGroup() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"max_connections": {
-							Type:        schema.TypeString,
+							Type:        schema.TypeInt,
 							Description: "Maximum number of total simultaneous connections allowed",
 							Computed:    true,
 						},
 						"max_connections_per_user": {
-							Type:        schema.TypeString,
+							Type:        schema.TypeInt,
 							Description: "Maximum number of simultaneous connections allowed per user",
 							Computed:    true,
 						},
@@ -141,7 +141,7 @@ func dataSourceConnectionGroup() *schema.Resource {
 }
 
 func dataSourceConnectionGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -189,6 +189,12 @@ func dataSourceConnectionGroupRead(ctx context.Context, d *schema.ResourceData,
 		return check
 	}
 
+	err := setExtraAttributes(d, client, connectionGroupObjectPath(group.Identifier), typedConnectionGroupAttributes, true)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	var memberGroups []interface{}
 	for _, group := range group.ChildGroups {
 		memberGroups = append(memberGroups, map[string]interface{}{
@@ -0,0 +1,68 @@
+package guacamole
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDataSourceConnectionFake(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+
+	group := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "Prod",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+
+	provider := Provider()
+	for _, protocol := range []string{"ssh", "telnet", "rdp", "vnc", "kubernetes"} {
+		config := map[string]interface{}{
+			"name":              protocol + "-01",
+			"parent_identifier": group.ID,
+			"network": []interface{}{
+				map[string]interface{}{
+					"hostname": protocol + ".example.com",
+				},
+			},
+		}
+		if protocol != "kubernetes" {
+			config["authentication"] = []interface{}{
+				map[string]interface{}{
+					"username": "admin",
+				},
+			}
+		}
+		connection := testFakeApply(t, provider.ResourcesMap["guacamole_connection_"+protocol], nil, config, client)
+
+		r := provider.DataSourcesMap["guacamole_connection_"+protocol]
+		for _, lookup := range []map[string]string{{"identifier": connection.ID}, {"path": "Prod/" + protocol + "-01"}} {
+			d := r.TestResourceData()
+			for k, v := range lookup {
+				d.Set(k, v)
+			}
+			diags := r.ReadContext(context.Background(), d, client)
+			if diags.HasError() {
+				t.Fatalf("unable to read %s connection by %v: %v", protocol, lookup, diags)
+			}
+			if d.Id() != connection.ID || d.Get("protocol").(string) != protocol {
+				t.Errorf("expected %s connection %s by %v, got %s connection %q", protocol, connection.ID, lookup, d.Get("protocol"), d.Id())
+			}
+			if hostname := d.Get("network.0.hostname").(string); hostname != protocol+".example.com" {
+				t.Errorf("expected %s connection hostname %s.example.com, got %q", protocol, protocol, hostname)
+			}
+		}
+	}
+
+	r := dataSourceConnectionSSH()
+	for _, lookup := range []map[string]string{{}, {"identifier": "1", "path": "Prod/ssh-01"}} {
+		d := r.TestResourceData()
+		for k, v := range lookup {
+			d.Set(k, v)
+		}
+		diags := r.ReadContext(context.Background(), d, client)
+		if !diags.HasError() || !strings.Contains(diags[0].Detail, "Either `identifier` or `path` must be specified") {
+			t.Errorf("expected an error for identifier and path %v, got %v", lookup, diags)
+		}
+	}
+}
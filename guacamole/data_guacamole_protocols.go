@@ -0,0 +1,165 @@
+package guacamole
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+func dataSourceProtocols() *schema.Resource {
+	formSchema := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the form",
+				Computed:    true,
+			},
+			"fields": {
+				Type:        schema.TypeList,
+				Description: "Fields of the form",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Parameter name of the field",
+							Computed:    true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the field",
+							Computed:    true,
+						},
+						"options": {
+							Type:        schema.TypeList,
+							Description: "Allowed values of enumerated fields",
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceProtocolsRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Only return the protocol with this name",
+				Optional:    true,
+			},
+			"protocols": {
+				Type:        schema.TypeList,
+				Description: "Protocols supported by the guacamole server",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "Name of the protocol",
+							Computed:    true,
+						},
+						"connection_forms": {
+							Type:        schema.TypeList,
+							Description: "Connection parameter forms of the protocol",
+							Computed:    true,
+							Elem:        formSchema,
+						},
+						"sharing_profile_forms": {
+							Type:        schema.TypeList,
+							Description: "Sharing profile parameter forms of the protocol",
+							Computed:    true,
+							Elem:        formSchema,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceProtocolsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	name := d.Get("name").(string)
+
+	schemas, err := client.GetProtocolSchemas()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var names []string
+	for _, protocol := range schemas {
+		if name == "" || protocol.Name == name {
+			names = append(names, protocol.Name)
+		}
+	}
+	sort.Strings(names)
+
+	if name != "" && len(names) == 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Protocol not found",
+			Detail:   fmt.Sprintf("The guacamole server does not support the protocol: %s", name),
+		})
+		return diags
+	}
+
+	var protocols []interface{}
+	for _, protocolName := range names {
+		for _, protocol := range schemas {
+			if protocol.Name != protocolName {
+				continue
+			}
+			var connectionForms []interface{}
+			for _, form := range protocol.ConnectionForms {
+				connectionForms = append(connectionForms, flattenProtocolForm(form.Name, form.Fields))
+			}
+			var sharingProfileForms []interface{}
+			for _, form := range protocol.SharingProfileForms {
+				sharingProfileForms = append(sharingProfileForms, flattenProtocolForm(form.Name, form.Fields))
+			}
+			protocols = append(protocols, map[string]interface{}{
+				"name":                  protocol.Name,
+				"connection_forms":      connectionForms,
+				"sharing_profile_forms": sharingProfileForms,
+			})
+		}
+	}
+
+	d.Set("protocols", protocols)
+
+	if name != "" {
+		d.SetId(name)
+	} else {
+		d.SetId("protocols")
+	}
+
+	return diags
+}
+
+func flattenProtocolForm(name string, fields []types.ConnectionFormField) map[string]interface{} {
+	var flattened []interface{}
+	for _, field := range fields {
+		flattened = append(flattened, map[string]interface{}{
+			"name":    field.Name,
+			"type":    field.Type,
+			"options": field.Options,
+		})
+	}
+	return map[string]interface{}{
+		"name":   name,
+		"fields": flattened,
+	}
+}
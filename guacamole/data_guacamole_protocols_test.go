@@ -0,0 +1,109 @@
+package guacamole
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+func TestDataSourceProtocolsFake(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+	r := dataSourceProtocols()
+
+	d := r.TestResourceData()
+	diags := r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read protocols: %v", diags)
+	}
+	var names []string
+	for _, protocol := range d.Get("protocols").([]interface{}) {
+		names = append(names, protocol.(map[string]interface{})["name"].(string))
+	}
+	expected := append([]string{}, connectionProtocols...)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected protocols %v sorted by name, got %v", expected, names)
+	}
+
+	d = r.TestResourceData()
+	d.Set("name", "ssh")
+	diags = r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read the ssh protocol: %v", diags)
+	}
+	if d.Id() != "ssh" || d.Get("protocols.#").(int) != 1 || d.Get("protocols.0.name").(string) != "ssh" {
+		t.Fatalf("expected only the ssh protocol, got %v", d.Get("protocols"))
+	}
+
+	// every form of the fixture is read, down to the options of enumerated fields
+	var schemas map[string]types.ProtocolSchema
+	if err := json.Unmarshal(fakeProtocolSchemas, &schemas); err != nil {
+		t.Fatalf("unable to decode the protocols fixture: %s", err)
+	}
+	var connectionForms, sharingProfileForms []interface{}
+	for _, form := range schemas["ssh"].ConnectionForms {
+		connectionForms = append(connectionForms, flattenProtocolForm(form.Name, form.Fields))
+	}
+	for _, form := range schemas["ssh"].SharingProfileForms {
+		sharingProfileForms = append(sharingProfileForms, flattenProtocolForm(form.Name, form.Fields))
+	}
+	if !reflect.DeepEqual(d.Get("protocols.0.connection_forms"), testProtocolForms(connectionForms)) {
+		t.Errorf("expected the ssh connection forms %v, got %v", connectionForms, d.Get("protocols.0.connection_forms"))
+	}
+	if !reflect.DeepEqual(d.Get("protocols.0.sharing_profile_forms"), testProtocolForms(sharingProfileForms)) {
+		t.Errorf("expected the ssh sharing profile forms %v, got %v", sharingProfileForms, d.Get("protocols.0.sharing_profile_forms"))
+	}
+
+	fields := make(map[string]map[string]interface{})
+	for _, form := range d.Get("protocols.0.connection_forms").([]interface{}) {
+		for _, field := range form.(map[string]interface{})["fields"].([]interface{}) {
+			field := field.(map[string]interface{})
+			fields[field["name"].(string)] = field
+		}
+	}
+	expectedFields := map[string]map[string]interface{}{
+		"hostname":      {"name": "hostname", "type": "TEXT", "options": []interface{}{}},
+		"port":          {"name": "port", "type": "NUMERIC", "options": []interface{}{}},
+		"username":      {"name": "username", "type": "USERNAME", "options": []interface{}{}},
+		"private-key":   {"name": "private-key", "type": "MULTILINE", "options": []interface{}{}},
+		"read-only":     {"name": "read-only", "type": "BOOLEAN", "options": []interface{}{"true"}},
+		"terminal-type": {"name": "terminal-type", "type": "ENUM", "options": []interface{}{"", "xterm", "xterm-256color", "vt220", "vt100", "ansi", "linux"}},
+	}
+	for name, field := range expectedFields {
+		if !reflect.DeepEqual(fields[name], field) {
+			t.Errorf("expected ssh field %v, got %v", field, fields[name])
+		}
+	}
+	sharing := d.Get("protocols.0.sharing_profile_forms.0").(map[string]interface{})
+	if sharing["name"] != "display" || !reflect.DeepEqual(sharing["fields"], []interface{}{expectedFields["read-only"]}) {
+		t.Errorf("expected the ssh sharing profile form display with the field read-only, got %v", sharing)
+	}
+
+	d = r.TestResourceData()
+	d.Set("name", "spice")
+	diags = r.ReadContext(context.Background(), d, client)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "does not support the protocol: spice") {
+		t.Fatalf("expected an unsupported protocol to fail, got %v", diags)
+	}
+}
+
+// testProtocolForms returns flattened protocol forms the way they read back from the data source,
+// with missing options as empty lists
+func testProtocolForms(forms []interface{}) []interface{} {
+	for _, form := range forms {
+		for _, field := range form.(map[string]interface{})["fields"].([]interface{}) {
+			field := field.(map[string]interface{})
+			options := []interface{}{}
+			for _, option := range field["options"].([]string) {
+				options = append(options, option)
+			}
+			field["options"] = options
+		}
+	}
+	return forms
+}
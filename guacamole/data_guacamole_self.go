@@ -0,0 +1,155 @@
+package guacamole
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+func dataSourceSelf() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSelfRead,
+		Schema: map[string]*schema.Schema{
+			"require_system_permissions": {
+				Type:        schema.TypeSet,
+				Description: "System permissions the authenticated user must hold",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(types.SystemPermissions{}.ValidChoices(), false)),
+				},
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Description: "Username of the authenticated user",
+				Computed:    true,
+			},
+			"data_source": {
+				Type:        schema.TypeString,
+				Description: "Data source selected for the session",
+				Computed:    true,
+			},
+			"available_data_sources": {
+				Type:        schema.TypeList,
+				Description: "Data sources available to the session",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"system_permissions": {
+				Type:        schema.TypeSet,
+				Description: "Effective system permissions of the authenticated user",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"connections": {
+				Type:        schema.TypeSet,
+				Description: "Connection identifiers the authenticated user has effective permissions on",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"connection_groups": {
+				Type:        schema.TypeSet,
+				Description: "Connection group identifiers the authenticated user has effective permissions on",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"users": {
+				Type:        schema.TypeSet,
+				Description: "Usernames the authenticated user has effective permissions on",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_groups": {
+				Type:        schema.TypeSet,
+				Description: "User group identifiers the authenticated user has effective permissions on",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSelfRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	username := client.session.Username
+	if username == "" {
+		self, err := client.ReadSelf()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		username = self.Username
+	}
+
+	permissions, err := client.GetEffectivePermissions()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Error reading effective permissions of guacamole user: %s", username),
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	var requiredPermissions []string
+	for _, permission := range d.Get("require_system_permissions").(*schema.Set).List() {
+		requiredPermissions = append(requiredPermissions, permission.(string))
+	}
+
+	// ADMINISTER implies every other system permission
+	if len(requiredPermissions) > 0 && !stringSliceContains(permissions.SystemPermissions, types.SystemPermissions{}.Administer()) {
+		missing := sliceDiff(requiredPermissions, permissions.SystemPermissions, false)
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Missing required system permissions",
+				Detail:   fmt.Sprintf("Guacamole user %s is missing the system permissions: %s", username, strings.Join(missing, ", ")),
+			})
+			return diags
+		}
+	}
+
+	d.Set("username", username)
+	d.Set("data_source", client.session.DataSource)
+	d.Set("available_data_sources", client.session.AvailableDataSources)
+	d.Set("system_permissions", permissions.SystemPermissions)
+	d.Set("connections", permissionIdentifiers(permissions.ConnectionPermissions))
+	d.Set("connection_groups", permissionIdentifiers(permissions.ConnectionGroupPermissions))
+	d.Set("users", permissionIdentifiers(permissions.UserPermissions))
+	d.Set("user_groups", permissionIdentifiers(permissions.UserGroupPermissions))
+
+	d.SetId(username)
+
+	return diags
+}
+
+func permissionIdentifiers(permissions map[string][]string) []string {
+	var identifiers []string
+	for identifier := range permissions {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+	return identifiers
+}
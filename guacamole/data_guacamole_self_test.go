@@ -0,0 +1,95 @@
+package guacamole
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	guac "github.com/techBeck03/guacamole-api-client"
+)
+
+func TestDataSourceSelfFake(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+	r := dataSourceSelf()
+
+	// ADMINISTER holds every system permission, so guacadmin meets any requirement
+	d := r.TestResourceData()
+	d.Set("require_system_permissions", []interface{}{"CREATE_USER", "CREATE_CONNECTION"})
+	diags := r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read self: %v", diags)
+	}
+	if d.Get("username").(string) != fakeGuacamoleUsername || d.Get("data_source").(string) != fakeGuacamoleDataSource {
+		t.Fatalf("expected %s on %s, got %q on %q", fakeGuacamoleUsername, fakeGuacamoleDataSource, d.Get("username"), d.Get("data_source"))
+	}
+
+	web := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name":              "web",
+		"parent_identifier": "ROOT",
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "web.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "web",
+			},
+		},
+	}, client)
+	testFakeApply(t, guacamoleUserGroup(), nil, map[string]interface{}{
+		"identifier":         "creators",
+		"system_permissions": []interface{}{"CREATE_USER"},
+	}, client)
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username":           "limited",
+		"password":           "limited-password",
+		"system_permissions": []interface{}{"CREATE_CONNECTION"},
+		"group_membership":   []interface{}{"creators"},
+		"connections":        []interface{}{web.ID},
+	}, client)
+
+	limited, err := newGuacamoleClient(guac.Config{
+		URL:      f.server.URL,
+		Username: "limited",
+		Password: "limited-password",
+	})
+	if err != nil {
+		t.Fatalf("unable to connect as limited: %s", err)
+	}
+
+	// permissions granted through user groups count towards the requirements
+	d = r.TestResourceData()
+	d.Set("require_system_permissions", []interface{}{"CREATE_USER", "CREATE_CONNECTION"})
+	diags = r.ReadContext(context.Background(), d, limited)
+	if diags.HasError() {
+		t.Fatalf("unable to read self as limited: %v", diags)
+	}
+	if d.Get("username").(string) != "limited" {
+		t.Fatalf("expected username limited, got %q", d.Get("username"))
+	}
+	expected := map[string][]string{
+		"system_permissions": {"CREATE_CONNECTION", "CREATE_USER"},
+		"connections":        {web.ID},
+	}
+	for k, v := range expected {
+		var actual []string
+		for _, value := range d.Get(k).(*schema.Set).List() {
+			actual = append(actual, value.(string))
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, v) {
+			t.Errorf("expected %s %v, got %v", k, v, actual)
+		}
+	}
+
+	d = r.TestResourceData()
+	d.Set("require_system_permissions", []interface{}{"CREATE_USER", "ADMINISTER", "CREATE_USER_GROUP"})
+	diags = r.ReadContext(context.Background(), d, limited)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "missing the system permissions: ADMINISTER, CREATE_USER_GROUP") {
+		t.Fatalf("expected missing system permissions to be reported, got %v", diags)
+	}
+}
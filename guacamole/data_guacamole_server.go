@@ -0,0 +1,170 @@
+package guacamole
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// attribute schema object types keyed by their attribute name
+var serverAttributeSchemas = map[string]string{
+	"user_attributes":             "user",
+	"user_group_attributes":       "userGroup",
+	"connection_attributes":       "connection",
+	"connection_group_attributes": "connectionGroup",
+	"sharing_profile_attributes":  "sharingProfile",
+}
+
+func dataSourceServer() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"version": {
+			Type:        schema.TypeString,
+			Description: "Version of guacamole, empty when the web application doesn't report it",
+			Computed:    true,
+		},
+		"data_source": {
+			Type:        schema.TypeString,
+			Description: "Data source selected for the session",
+			Computed:    true,
+		},
+		"available_data_sources": {
+			Type:        schema.TypeList,
+			Description: "Data sources available to the session",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"languages": {
+			Type:        schema.TypeMap,
+			Description: "Interface languages keyed by language code",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"patches": {
+			Type:        schema.TypeList,
+			Description: "HTML patches installed by extensions",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"extensions": {
+			Type:        schema.TypeList,
+			Description: "Extensions detected from data sources and attribute schemas",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+	for k, v := range serverAttributeSchemas {
+		s[k] = &schema.Schema{
+			Type:        schema.TypeList,
+			Description: "Attribute names recognized for " + v + " objects",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceServerRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	languages, err := client.GetLanguages()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	patches, err := client.GetPatches()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Reverse proxies may only expose the REST API, so a missing version isn't an error
+	version, err := client.GetVersion()
+	if err != nil {
+		log.Printf("[WARN] unable to read guacamole version: %s", err)
+		version = ""
+	}
+
+	attributes := make(map[string][]string)
+	for k, objectType := range serverAttributeSchemas {
+		forms, err := client.GetAttributeSchema(objectType)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		var names []string
+		for _, form := range forms {
+			for _, field := range form.Fields {
+				names = append(names, field.Name)
+			}
+		}
+		sort.Strings(names)
+		attributes[k] = names
+		d.Set(k, names)
+	}
+
+	d.Set("version", version)
+	d.Set("data_source", client.session.DataSource)
+	d.Set("available_data_sources", client.session.AvailableDataSources)
+	d.Set("languages", languages)
+	d.Set("patches", patches)
+	d.Set("extensions", detectExtensions(client.session.AvailableDataSources, attributes))
+
+	d.SetId(client.config.URL)
+
+	return diags
+}
+
+// detectExtensions infers installed extensions from the data sources they register
+// and the attributes they contribute
+func detectExtensions(dataSources []string, attributes map[string][]string) []string {
+	detected := make(map[string]bool)
+
+	for _, dataSource := range dataSources {
+		switch dataSource {
+		case "mysql", "postgresql", "sqlserver":
+			detected["jdbc-"+dataSource] = true
+		case "ldap", "quickconnect", "json":
+			detected[dataSource] = true
+		}
+	}
+
+	attributePrefixes := map[string]string{
+		"guac-totp-": "totp",
+		"ksm-":       "vault-ksm",
+	}
+	for _, names := range attributes {
+		for _, name := range names {
+			for prefix, extension := range attributePrefixes {
+				if strings.HasPrefix(name, prefix) {
+					detected[extension] = true
+				}
+			}
+		}
+	}
+
+	var extensions []string
+	for extension := range detected {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
@@ -0,0 +1,37 @@
+package guacamole
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDataSourceServerFakeVersion(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	r := dataSourceServer()
+	d := r.TestResourceData()
+	diags := r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read server: %v", diags)
+	}
+	if version := d.Get("version").(string); version != fakeGuacamoleVersion {
+		t.Fatalf("expected version %s, got %q", fakeGuacamoleVersion, version)
+	}
+	if dataSource := d.Get("data_source").(string); dataSource != fakeGuacamoleDataSource {
+		t.Fatalf("expected data source %s, got %q", fakeGuacamoleDataSource, dataSource)
+	}
+
+	// servers that don't serve the web application still read without a version
+	f.mu.Lock()
+	f.version = ""
+	f.mu.Unlock()
+
+	d = r.TestResourceData()
+	diags = r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unable to read server without a version: %v", diags)
+	}
+	if version := d.Get("version").(string); version != "" {
+		t.Fatalf("expected no version, got %q", version)
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
 )
 
 func dataSourceUser() *schema.Resource {
@@ -23,6 +22,7 @@ func dataSourceUser() *schema.Resource {
 				Description: "Epoch time string of last user activity",
 				Computed:    true,
 			},
+			"extra_attributes": dataSourceExtraAttributesSchema(),
 			"attributes": {
 				Type:        schema.TypeList,
 				Description: "Attributes of guacamole user",
@@ -119,7 +119,7 @@ func dataSourceUser() *schema.Resource {
 }
 
 func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -152,6 +152,12 @@ func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
+	err = setExtraAttributes(d, client, userObjectPath(username), typedUserAttributes, true)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	permissions, err := client.GetUserPermissions(username)
 
 	if err != nil {
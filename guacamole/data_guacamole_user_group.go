@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
 )
 
 func dataSourceUserGroup() *schema.Resource {
@@ -18,6 +17,7 @@ func dataSourceUserGroup() *schema.Resource {
 				Description: "Identifier of guacamole user group",
 				Required:    true,
 			},
+			"extra_attributes": dataSourceExtraAttributesSchema(),
 			"attributes": {
 				Type:        schema.TypeList,
 				Description: "Attributes of guacamole user group",
@@ -85,7 +85,7 @@ func dataSourceUserGroup() *schema.Resource {
 }
 
 func dataSourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -110,6 +110,12 @@ func dataSourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.FromErr(err)
 	}
 
+	err = setExtraAttributes(d, client, userGroupObjectPath(identifier), typedUserGroupAttributes, true)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	parentGroups, err := client.GetUserGroupParentGroups(identifier)
 
 	if err != nil {
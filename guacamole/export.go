@@ -0,0 +1,565 @@
+package guacamole
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	guac "github.com/techBeck03/guacamole-api-client"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+// exportConnectionResource maps a connection protocol to its resource
+type exportConnectionResource struct {
+	resourceType string
+	resource     func() *schema.Resource
+}
+
+var exportConnectionResources = map[string]exportConnectionResource{
+	"ssh":        {"guacamole_connection_ssh", guacamoleConnectionSSH},
+	"telnet":     {"guacamole_connection_telnet", guacamoleConnectionTelnet},
+	"rdp":        {"guacamole_connection_rdp", guacamoleConnectionRDP},
+	"vnc":        {"guacamole_connection_vnc", guacamoleConnectionVNC},
+	"kubernetes": {"guacamole_connection_kubernetes", guacamoleConnectionKubernetes},
+}
+
+// exportSecretFields lists the fields exported as variables rather than literals, the user
+// password and the sensitive connection parameters in both their section block and the
+// parameters block
+var exportSecretFields = func() []string {
+	fields := []string{"password"}
+	for _, p := range connectionParameters {
+		if !p.sensitive {
+			continue
+		}
+		for _, protocol := range p.protocols {
+			section, nested, _ := parameterSectionPath(connectionParameterSections(protocol), p.field)
+			for _, field := range []string{section + "." + nested, "parameters." + p.field} {
+				if !stringSliceContains(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+	return fields
+}()
+
+var exportLabelInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// exportedObject is a single resource block of the generated configuration
+type exportedObject struct {
+	resourceType string
+	label        string
+	importID     string
+	resource     *schema.Resource
+	data         *schema.ResourceData
+	references   map[string]string
+}
+
+func (o *exportedObject) address() string {
+	return fmt.Sprintf("%s.%s", o.resourceType, o.label)
+}
+
+// exporter walks a guacamole instance and renders it as terraform configuration
+type exporter struct {
+	client    *guacamoleClient
+	labels    map[string]bool
+	variables []string
+
+	groups      map[string]*exportedObject
+	connections map[string]*exportedObject
+	userGroups  map[string]*exportedObject
+	users       map[string]*exportedObject
+}
+
+// Export writes terraform configuration and import blocks for every connection group,
+// connection, user group and user of a guacamole instance to outDir
+func Export(config guac.Config, outDir string) error {
+	client, err := newGuacamoleClient(config)
+	if err != nil {
+		return err
+	}
+	// Only close sessions created by the export itself
+	if config.Token == "" {
+		defer client.Disconnect()
+	}
+
+	e := &exporter{
+		client:      client,
+		labels:      make(map[string]bool),
+		groups:      make(map[string]*exportedObject),
+		connections: make(map[string]*exportedObject),
+		userGroups:  make(map[string]*exportedObject),
+		users:       make(map[string]*exportedObject),
+	}
+
+	tree, err := client.GetConnectionTree("ROOT")
+	if err != nil {
+		return err
+	}
+
+	var groups, connections []*exportedObject
+	err = e.walkConnectionTree(tree, "", &groups, &connections)
+	if err != nil {
+		return err
+	}
+
+	userGroups, err := e.exportUserGroups()
+	if err != nil {
+		return err
+	}
+
+	users, err := e.exportUsers()
+	if err != nil {
+		return err
+	}
+
+	// References can only be resolved once every object has a label
+	for _, object := range groups {
+		e.referenceParent(object)
+	}
+	for _, object := range connections {
+		e.referenceParent(object)
+	}
+	for _, object := range append(append([]*exportedObject{}, userGroups...), users...) {
+		e.referencePermissions(object)
+	}
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		objects []*exportedObject
+	}{
+		{"connection_groups.tf", groups},
+		{"connections.tf", connections},
+		{"user_groups.tf", userGroups},
+		{"users.tf", users},
+	}
+
+	var imports []string
+	for _, file := range files {
+		var blocks []string
+		for _, object := range file.objects {
+			blocks = append(blocks, e.renderResource(object))
+			imports = append(imports, fmt.Sprintf("import {\n  to = %s\n  id = %s\n}\n", object.address(), hclQuote(object.importID)))
+		}
+		err = writeExportFile(outDir, file.name, blocks)
+		if err != nil {
+			return err
+		}
+	}
+
+	var variables []string
+	for _, variable := range e.variables {
+		variables = append(variables, fmt.Sprintf("variable %s {\n  type      = string\n  sensitive = true\n}\n", hclQuote(variable)))
+	}
+	err = writeExportFile(outDir, "variables.tf", variables)
+	if err != nil {
+		return err
+	}
+
+	return writeExportFile(outDir, "imports.tf", imports)
+}
+
+// walkConnectionTree collects the connection groups and connections below a tree node
+func (e *exporter) walkConnectionTree(node types.GuacConnectionGroup, path string, groups *[]*exportedObject, connections *[]*exportedObject) error {
+	childGroups := append([]types.GuacConnectionGroup{}, node.ChildGroups...)
+	sort.Slice(childGroups, func(i, j int) bool { return childGroups[i].Name < childGroups[j].Name })
+
+	for _, group := range childGroups {
+		groupPath := joinExportPath(path, group.Name)
+		resource := guacamoleConnectionGroup()
+		d := resource.Data(nil)
+		check := convertGuacConnectionGroupToResourceData(d, &group)
+		if check.HasError() {
+			return fmt.Errorf("unable to export connection group %s: %s", groupPath, check[0].Summary)
+		}
+		object := &exportedObject{
+			resourceType: "guacamole_connection_group",
+			label:        e.newLabel(groupPath),
+			importID:     group.Identifier,
+			resource:     resource,
+			data:         d,
+			references:   make(map[string]string),
+		}
+		*groups = append(*groups, object)
+		e.groups[group.Identifier] = object
+
+		err := e.walkConnectionTree(group, groupPath, groups, connections)
+		if err != nil {
+			return err
+		}
+	}
+
+	childConnections := append([]types.GuacConnection{}, node.ChildConnections...)
+	sort.Slice(childConnections, func(i, j int) bool { return childConnections[i].Name < childConnections[j].Name })
+
+	for _, child := range childConnections {
+		connectionPath := joinExportPath(path, child.Name)
+		mapping, ok := exportConnectionResources[child.Protocol]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "skipping connection %s: unsupported protocol %s\n", connectionPath, child.Protocol)
+			continue
+		}
+
+		connection, err := e.client.ReadConnection(child.Identifier)
+		if err != nil {
+			return err
+		}
+
+		parameters, err := e.client.ReadConnectionParameters(child.Identifier)
+		if err != nil {
+			return err
+		}
+
+		resource := mapping.resource()
+		d := resource.Data(nil)
+		check := convertGuacConnectionToResourceData(d, &connection, parameters, child.Protocol)
+		if check.HasError() {
+			return fmt.Errorf("unable to export connection %s: %s", connectionPath, check[0].Summary)
+		}
+		object := &exportedObject{
+			resourceType: mapping.resourceType,
+			label:        e.newLabel(connectionPath),
+			importID:     connection.Identifier,
+			resource:     resource,
+			data:         d,
+			references:   make(map[string]string),
+		}
+		*connections = append(*connections, object)
+		e.connections[connection.Identifier] = object
+	}
+
+	return nil
+}
+
+func (e *exporter) exportUserGroups() ([]*exportedObject, error) {
+	var objects []*exportedObject
+
+	userGroups, err := e.client.ListUserGroups()
+	if err != nil {
+		return objects, err
+	}
+	sort.Slice(userGroups, func(i, j int) bool { return userGroups[i].Identifier < userGroups[j].Identifier })
+
+	for _, group := range userGroups {
+		resource := guacamoleUserGroup()
+		d := resource.Data(nil)
+		err = convertGuacUserGroupToResourceData(d, &group)
+		if err != nil {
+			return objects, err
+		}
+
+		memberGroups, err := e.client.GetUserGroupMemberGroups(group.Identifier)
+		if err != nil {
+			return objects, err
+		}
+		d.Set("group_membership", memberGroups)
+
+		permissions, err := e.client.GetUserGroupPermissions(group.Identifier)
+		if err != nil {
+			return objects, err
+		}
+		setExportPermissions(d, &permissions)
+
+		object := &exportedObject{
+			resourceType: "guacamole_user_group",
+			label:        e.newLabel(group.Identifier),
+			importID:     group.Identifier,
+			resource:     resource,
+			data:         d,
+			references:   make(map[string]string),
+		}
+		objects = append(objects, object)
+		e.userGroups[group.Identifier] = object
+	}
+
+	return objects, nil
+}
+
+func (e *exporter) exportUsers() ([]*exportedObject, error) {
+	var objects []*exportedObject
+
+	users, err := e.client.ListUsers()
+	if err != nil {
+		return objects, err
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+
+	for _, user := range users {
+		// Managing the account running the export would let terraform delete it
+		if user.Username == e.client.session.Username {
+			continue
+		}
+
+		resource := guacamoleUser()
+		d := resource.Data(nil)
+		err = convertGuacUserToResourceData(d, &user)
+		if err != nil {
+			return objects, err
+		}
+
+		groups, err := e.client.GetUserGroupMembership(user.Username)
+		if err != nil {
+			return objects, err
+		}
+		d.Set("group_membership", groups)
+
+		permissions, err := e.client.GetUserPermissions(user.Username)
+		if err != nil {
+			return objects, err
+		}
+		setExportPermissions(d, &permissions)
+
+		object := &exportedObject{
+			resourceType: "guacamole_user",
+			label:        e.newLabel(user.Username),
+			importID:     user.Username,
+			resource:     resource,
+			data:         d,
+			references:   make(map[string]string),
+		}
+		objects = append(objects, object)
+		e.users[user.Username] = object
+	}
+
+	return objects, nil
+}
+
+func setExportPermissions(d *schema.ResourceData, permissions *types.GuacPermissionData) {
+	d.Set("system_permissions", permissions.SystemPermissions)
+	d.Set("connections", permissionIdentifiers(permissions.ConnectionPermissions))
+	d.Set("connection_groups", permissionIdentifiers(permissions.ConnectionGroupPermissions))
+}
+
+// referenceParent replaces the parent identifier literal with a reference to the exported parent group
+func (e *exporter) referenceParent(object *exportedObject) {
+	parent := object.data.Get("parent_identifier").(string)
+	if group, ok := e.groups[parent]; ok {
+		object.references["parent_identifier"] = group.address() + ".identifier"
+	}
+}
+
+// referencePermissions replaces identifier literals in memberships and permissions with references
+func (e *exporter) referencePermissions(object *exportedObject) {
+	lookups := map[string]map[string]*exportedObject{
+		"group_membership":  e.userGroups,
+		"connections":       e.connections,
+		"connection_groups": e.groups,
+	}
+
+	for key, lookup := range lookups {
+		var values []string
+		for _, v := range object.data.Get(key).(*schema.Set).List() {
+			values = append(values, v.(string))
+		}
+		if len(values) == 0 {
+			continue
+		}
+		sort.Strings(values)
+
+		var elements []string
+		for _, v := range values {
+			if target, ok := lookup[v]; ok {
+				elements = append(elements, target.address()+".identifier")
+			} else {
+				elements = append(elements, hclQuote(v))
+			}
+		}
+		object.references[key] = "[\n    " + strings.Join(elements, ",\n    ") + ",\n  ]"
+	}
+}
+
+// newLabel derives a unique resource label from a name
+func (e *exporter) newLabel(name string) string {
+	label := exportLabelInvalidChars.ReplaceAllString(strings.ToLower(name), "_")
+	label = strings.Trim(label, "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "r_" + label
+	}
+
+	unique := label
+	for i := 2; e.labels[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	e.labels[unique] = true
+
+	return unique
+}
+
+// renderResource renders an exported object as a resource block
+func (e *exporter) renderResource(object *exportedObject) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resource %s %s {\n", hclQuote(object.resourceType), hclQuote(object.label))
+	values := make(map[string]interface{})
+	for k := range object.resource.Schema {
+		values[k] = object.data.Get(k)
+	}
+	e.renderBody(&b, object, object.resource.Schema, values, "", 1)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (e *exporter) renderBody(b *strings.Builder, object *exportedObject, s map[string]*schema.Schema, values map[string]interface{}, prefix string, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	var keys []string
+	for k, v := range s {
+		// Skip attributes that can't be configured
+		if v.Computed && !v.Optional && !v.Required {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Arguments are written before nested blocks
+	var blocks []string
+	for _, k := range keys {
+		field := prefix + k
+		if _, ok := s[k].Elem.(*schema.Resource); ok {
+			blocks = append(blocks, k)
+			continue
+		}
+
+		if expression, ok := object.references[field]; ok {
+			fmt.Fprintf(b, "%s%s = %s\n", indent, k, expression)
+			continue
+		}
+
+		value := values[k]
+		if isZeroValue(value) && !s[k].Required {
+			continue
+		}
+
+		if stringSliceContains(exportSecretFields, field) {
+			variable := fmt.Sprintf("%s_%s", object.label, strings.ReplaceAll(field, ".", "_"))
+			e.variables = append(e.variables, variable)
+			fmt.Fprintf(b, "%s%s = var.%s\n", indent, k, variable)
+			continue
+		}
+
+		fmt.Fprintf(b, "%s%s = %s\n", indent, k, hclValue(value))
+	}
+
+	for _, k := range blocks {
+		var items []interface{}
+		switch v := values[k].(type) {
+		case []interface{}:
+			items = v
+		case *schema.Set:
+			items = v.List()
+		}
+		for _, item := range items {
+			itemValues, ok := item.(map[string]interface{})
+			if !ok || !hasNonZeroExportValue(itemValues) {
+				continue
+			}
+			fmt.Fprintf(b, "%s%s {\n", indent, k)
+			e.renderBody(b, object, s[k].Elem.(*schema.Resource).Schema, itemValues, prefix+k+".", depth+1)
+			fmt.Fprintf(b, "%s}\n", indent)
+		}
+	}
+}
+
+func hasNonZeroExportValue(values map[string]interface{}) bool {
+	for _, v := range values {
+		if !isZeroValue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// hclValue renders a primitive, list or map value as an hcl expression
+func hclValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return hclQuote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *schema.Set:
+		var elements []string
+		for _, element := range v.List() {
+			elements = append(elements, hclValue(element))
+		}
+		sort.Strings(elements)
+		return "[" + strings.Join(elements, ", ") + "]"
+	case []interface{}:
+		var elements []string
+		for _, element := range v {
+			elements = append(elements, hclValue(element))
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case map[string]interface{}:
+		var keys []string
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var elements []string
+		for _, k := range keys {
+			elements = append(elements, fmt.Sprintf("%s = %s", hclQuote(k), hclValue(v[k])))
+		}
+		return "{ " + strings.Join(elements, ", ") + " }"
+	}
+	return hclQuote(fmt.Sprintf("%v", value))
+}
+
+// hclQuote renders a string as a quoted hcl template that evaluates to the literal string
+func hclQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && i+1 < len(runes) && runes[i+1] == '{':
+			// Escape template sequences such as guacamole parameter tokens
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func joinExportPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+func writeExportFile(outDir string, name string, blocks []string) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(outDir, name), []byte(strings.Join(blocks, "\n")), 0644)
+}
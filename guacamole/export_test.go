@@ -0,0 +1,214 @@
+package guacamole
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	guac "github.com/techBeck03/guacamole-api-client"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestExportFake(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	prod := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "Prod Servers",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+	database := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "DB",
+		"parent_identifier": prod.ID,
+		"type":              "BALANCING",
+	}, client)
+	testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "prod-servers",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+	web := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name":              "web-01",
+		"parent_identifier": prod.ID,
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "web-01.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "${GUAC_USERNAME}",
+				"password": "hunter2",
+			},
+		},
+		"session": []interface{}{
+			map[string]interface{}{
+				"execute_command": "echo %{ok} \"$HOME\"",
+			},
+		},
+	}, client)
+	testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name": "9 lives",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 1,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "lives.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "cat",
+			},
+		},
+	}, client)
+	testFakeApply(t, guacamoleUserGroup(), nil, map[string]interface{}{
+		"identifier":         "ops",
+		"connection_groups":  []interface{}{database.ID},
+		"system_permissions": []interface{}{"CREATE_CONNECTION"},
+	}, client)
+	testFakeApply(t, guacamoleUser(), nil, map[string]interface{}{
+		"username": "alice@example.com",
+		"password": "alice-password",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"full_name": "Alice",
+			},
+		},
+		"group_membership": []interface{}{"ops"},
+		"connections":      []interface{}{web.ID},
+	}, client)
+
+	dir := t.TempDir()
+	err := Export(guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	}, dir)
+	if err != nil {
+		t.Fatalf("unable to export: %s", err)
+	}
+
+	files := make(map[string]string)
+	for _, name := range []string{"connection_groups.tf", "connections.tf", "user_groups.tf", "users.tf", "variables.tf", "imports.tf"} {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected %s to be generated: %s", name, err)
+		}
+		files[name] = string(raw)
+	}
+
+	expected := map[string][]string{
+		"connection_groups.tf": {
+			`resource "guacamole_connection_group" "prod_servers" {`,
+			`resource "guacamole_connection_group" "prod_servers_db" {`,
+			`resource "guacamole_connection_group" "prod_servers_2" {`,
+			`  parent_identifier = guacamole_connection_group.prod_servers.identifier`,
+		},
+		"connections.tf": {
+			`resource "guacamole_connection_ssh" "prod_servers_web_01" {`,
+			`resource "guacamole_connection_ssh" "r_9_lives" {`,
+			`    username = "$${GUAC_USERNAME}"`,
+			`    execute_command = "echo %%{ok} \"$HOME\""`,
+			`    password = var.prod_servers_web_01_authentication_password`,
+			`  parent_identifier = guacamole_connection_group.prod_servers.identifier`,
+		},
+		"user_groups.tf": {
+			`resource "guacamole_user_group" "ops" {`,
+			"  connection_groups = [\n    guacamole_connection_group.prod_servers_db.identifier,\n  ]",
+		},
+		"users.tf": {
+			`resource "guacamole_user" "alice_example_com" {`,
+			"  group_membership = [\n    guacamole_user_group.ops.identifier,\n  ]",
+			"  connections = [\n    guacamole_connection_ssh.prod_servers_web_01.identifier,\n  ]",
+		},
+		"variables.tf": {
+			"variable \"prod_servers_web_01_authentication_password\" {\n  type      = string\n  sensitive = true\n}\n",
+		},
+		"imports.tf": {
+			"import {\n  to = guacamole_connection_ssh.prod_servers_web_01\n  id = \"" + web.ID + "\"\n}\n",
+			"import {\n  to = guacamole_user.alice_example_com\n  id = \"alice@example.com\"\n}\n",
+		},
+	}
+	for name, contents := range expected {
+		for _, content := range contents {
+			if !strings.Contains(files[name], content) {
+				t.Errorf("expected %s to contain %q, got:\n%s", name, content, files[name])
+			}
+		}
+	}
+	if strings.Contains(files["users.tf"], fakeGuacamoleUsername) {
+		t.Errorf("expected the exporting account to be left out, got:\n%s", files["users.tf"])
+	}
+	if strings.Contains(files["connections.tf"], "hunter2") {
+		t.Errorf("expected connection secrets to be exported as variables, got:\n%s", files["connections.tf"])
+	}
+	if t.Failed() {
+		return
+	}
+
+	// every exported object imports and plans no changes with the generated configuration
+	imports := testExportImports(t, files["imports.tf"])
+	variables := map[string]cty.Value{
+		"prod_servers_web_01_authentication_password": cty.StringVal("hunter2"),
+	}
+	resources := make(map[string]map[string]string)
+	for address, id := range imports {
+		resources[address] = map[string]string{"identifier": id}
+	}
+	ctx := testHCLEvalContext(resources, variables)
+
+	provider := Provider()
+	for _, name := range []string{"connection_groups.tf", "connections.tf", "user_groups.tf", "users.tf"} {
+		for _, block := range testHCLParse(t, name, files[name]).Blocks {
+			address := block.Labels[0] + "." + block.Labels[1]
+			id, ok := imports[address]
+			if !ok {
+				t.Fatalf("expected an import block for %s", address)
+			}
+			r := provider.ResourcesMap[block.Labels[0]]
+
+			d := r.Data(&terraform.InstanceState{ID: id})
+			imported, err := r.Importer.StateContext(context.Background(), d, client)
+			if err != nil {
+				t.Fatalf("unable to import %s: %s", address, err)
+			}
+			state := imported[0].State()
+			state.Ephemeral.Type = block.Labels[0]
+
+			testFakeCheckNoChanges(t, r, state, testHCLConfig(t, block.Body, ctx), client)
+		}
+	}
+}
+
+// testExportImports returns the import ids of the import blocks by resource address
+func testExportImports(t *testing.T, content string) map[string]string {
+	t.Helper()
+
+	imports := make(map[string]string)
+	for _, block := range testHCLParse(t, "imports.tf", content).Blocks {
+		traversal, diags := hcl.AbsTraversalForExpr(block.Body.Attributes["to"].Expr)
+		if diags.HasErrors() {
+			t.Fatalf("unable to read import address: %s", diags.Error())
+		}
+		id, diags := block.Body.Attributes["id"].Expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatalf("unable to read import id: %s", diags.Error())
+		}
+		address := traversal.RootName() + "." + traversal[1].(hcl.TraverseAttr).Name
+		imports[address] = id.AsString()
+	}
+	return imports
+}
@@ -0,0 +1,1185 @@
+package guacamole
+
+import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	guac "github.com/techBeck03/guacamole-api-client"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// fakeProtocolSchemas is the response of guacamole 1.5 to /schema/protocols, the forms of the
+// protocols guacamole ships with rather than the parameters the provider knows about
+//
+//go:embed testdata/schema_protocols.json
+var fakeProtocolSchemas []byte
+
+const (
+	fakeGuacamoleDataSource = "postgresql"
+	fakeGuacamoleUsername   = "guacadmin"
+	fakeGuacamolePassword   = "guacadmin"
+	fakeGuacamoleVersion    = "1.5.5"
+)
+
+// fakeGuacamole is an in-memory implementation of the guacamole rest api covering what the
+// provider uses, so that tests can run without a live guacamole
+type fakeGuacamole struct {
+	server *httptest.Server
+
+	mu               sync.Mutex
+	sessions         map[string]string
+	users            map[string]*fakeUser
+	userGroups       map[string]*fakeUserGroup
+	connections      map[string]*fakeConnection
+	connectionGroups map[string]*fakeConnectionGroup
+	nextConnection   int
+	nextGroup        int
+
+	// requests counts the requests received by method and path below /api
+	requests map[string]int
+
+	// totp adds the user attributes of the TOTP extension to the attribute schemas
+	totp bool
+
+	// version is reported by the english translation of the web application, which isn't
+	// served when empty
+	version string
+}
+
+// fakePermissions holds the permissions granted to a user or user group
+type fakePermissions struct {
+	system  map[string]bool
+	objects map[string]map[string]map[string]bool
+}
+
+type fakeUser struct {
+	username    string
+	password    string
+	attributes  map[string]*string
+	permissions *fakePermissions
+}
+
+type fakeUserGroup struct {
+	identifier   string
+	attributes   map[string]*string
+	permissions  *fakePermissions
+	memberUsers  map[string]bool
+	memberGroups map[string]bool
+}
+
+type fakeConnection struct {
+	identifier       string
+	name             string
+	parentIdentifier string
+	protocol         string
+	attributes       map[string]*string
+	parameters       map[string]string
+}
+
+type fakeConnectionGroup struct {
+	identifier       string
+	name             string
+	parentIdentifier string
+	groupType        string
+	attributes       map[string]*string
+}
+
+// fakeObject is the body of a request creating or updating an object
+type fakeObject struct {
+	Username         string             `json:"username"`
+	Password         string             `json:"password"`
+	Identifier       string             `json:"identifier"`
+	Name             string             `json:"name"`
+	ParentIdentifier string             `json:"parentIdentifier"`
+	Protocol         string             `json:"protocol"`
+	Type             string             `json:"type"`
+	Attributes       map[string]*string `json:"attributes"`
+	Parameters       map[string]*string `json:"parameters"`
+}
+
+// fakeAttributeSchemas lists the attributes guacamole stores for each object type
+var fakeAttributeSchemas = map[string][]types.ConnectionForm{
+	"user": {
+		{Name: "profile", Fields: []types.ConnectionFormField{
+			{Name: "guac-full-name", Type: "TEXT"},
+			{Name: "guac-email-address", Type: "EMAIL"},
+			{Name: "guac-organizational-role", Type: "TEXT"},
+		}},
+		{Name: "restrictions", Fields: []types.ConnectionFormField{
+			{Name: "disabled", Type: "BOOLEAN", Options: []string{"true"}},
+			{Name: "expired", Type: "BOOLEAN", Options: []string{"true"}},
+			{Name: "access-window-start", Type: "TIME"},
+			{Name: "access-window-end", Type: "TIME"},
+			{Name: "valid-from", Type: "DATE"},
+			{Name: "valid-until", Type: "DATE"},
+			{Name: "timezone", Type: "TIMEZONE"},
+		}},
+	},
+	"userGroup": {
+		{Name: "restrictions", Fields: []types.ConnectionFormField{
+			{Name: "disabled", Type: "BOOLEAN", Options: []string{"true"}},
+		}},
+	},
+	"connection": {
+		{Name: "concurrency", Fields: []types.ConnectionFormField{
+			{Name: "max-connections", Type: "NUMERIC"},
+			{Name: "max-connections-per-user", Type: "NUMERIC"},
+		}},
+		{Name: "load-balancing", Fields: []types.ConnectionFormField{
+			{Name: "weight", Type: "NUMERIC"},
+			{Name: "failover-only", Type: "BOOLEAN", Options: []string{"true"}},
+		}},
+		{Name: "guacd", Fields: []types.ConnectionFormField{
+			{Name: "guacd-hostname", Type: "TEXT"},
+			{Name: "guacd-port", Type: "NUMERIC"},
+			{Name: "guacd-encryption", Type: "ENUM", Options: []string{"", "none", "ssl"}},
+		}},
+	},
+	"connectionGroup": {
+		{Name: "concurrency", Fields: []types.ConnectionFormField{
+			{Name: "max-connections", Type: "NUMERIC"},
+			{Name: "max-connections-per-user", Type: "NUMERIC"},
+			{Name: "enable-session-affinity", Type: "BOOLEAN", Options: []string{"true"}},
+		}},
+	},
+	"sharingProfile": {},
+}
+
+// fakeGuacamoleError is the error body returned by guacamole
+type fakeGuacamoleError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+func newFakePermissions() *fakePermissions {
+	return &fakePermissions{
+		system:  make(map[string]bool),
+		objects: make(map[string]map[string]map[string]bool),
+	}
+}
+
+// newFakeGuacamole starts a fake guacamole holding a guacadmin administrator and an empty
+// connection tree
+func newFakeGuacamole() *fakeGuacamole {
+	f := &fakeGuacamole{
+		sessions:         make(map[string]string),
+		users:            make(map[string]*fakeUser),
+		userGroups:       make(map[string]*fakeUserGroup),
+		connections:      make(map[string]*fakeConnection),
+		connectionGroups: make(map[string]*fakeConnectionGroup),
+		requests:         make(map[string]int),
+		version:          fakeGuacamoleVersion,
+	}
+
+	admin := &fakeUser{
+		username:    fakeGuacamoleUsername,
+		password:    fakeGuacamolePassword,
+		attributes:  make(map[string]*string),
+		permissions: newFakePermissions(),
+	}
+	for _, p := range (types.SystemPermissions{}).ValidChoices() {
+		admin.permissions.system[p] = true
+	}
+	f.users[admin.username] = admin
+
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+// newTestFakeGuacamole starts a fake guacamole stopped at the end of a test, along with a
+// client authenticated against it as guacadmin
+func newTestFakeGuacamole(t *testing.T) (*fakeGuacamole, *guacamoleClient) {
+	t.Helper()
+
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+
+	client, err := newGuacamoleClient(guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	})
+	if err != nil {
+		t.Fatalf("unable to connect to fake guacamole: %s", err)
+	}
+
+	return f, client
+}
+
+// useFakeGuacamole points an acceptance test at a fake guacamole of its own, stopped at the end of
+// the test, unless a live guacamole is configured through GUACAMOLE_URL
+func useFakeGuacamole(t *testing.T) {
+	if os.Getenv("GUACAMOLE_URL") != "" {
+		return
+	}
+
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+	t.Logf("GUACAMOLE_URL is not set, running acceptance test against a fake guacamole at %s", f.server.URL)
+
+	f.setenv(t)
+}
+
+// setenv configures the provider of a test to connect to the fake guacamole as guacadmin
+func (f *fakeGuacamole) setenv(t *testing.T) {
+	for k, v := range map[string]string{
+		"GUACAMOLE_URL":         f.server.URL,
+		"GUACAMOLE_USERNAME":    fakeGuacamoleUsername,
+		"GUACAMOLE_PASSWORD":    fakeGuacamolePassword,
+		"GUACAMOLE_TOKEN":       "",
+		"GUACAMOLE_DATA_SOURCE": "",
+	} {
+		t.Setenv(k, v)
+	}
+}
+
+// testFakeApply validates a resource configuration, plans it against its current state and
+// applies the plan like terraform apply does, returning the new state.  Like terraform's
+// acceptance tests, planning the configuration again must yield no changes
+func testFakeApply(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	t.Helper()
+
+	if diags := r.Validate(terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("invalid configuration: %v", diags)
+	}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unable to plan: %s", err)
+	}
+	if diff == nil {
+		return state
+	}
+	// terraform hands the configuration to the apply along with the plan
+	diff.RawConfig = testFakeRawConfig(t, r, config)
+
+	next, diags := r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("unable to apply: %v", diags)
+	}
+	testFakeCheckNoChanges(t, r, next, config, meta)
+	return next
+}
+
+// testFakeRawConfig converts a resource configuration into the raw configuration terraform sends,
+// with the arguments that aren't configured being null
+func testFakeRawConfig(t *testing.T, r *schema.Resource, config map[string]interface{}) cty.Value {
+	t.Helper()
+
+	body, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("unable to encode configuration: %s", err)
+	}
+	raw, err := ctyjson.Unmarshal(body, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("unable to convert configuration: %s", err)
+	}
+	return raw
+}
+
+// testFakeDestroy destroys the resource of a state
+func testFakeDestroy(t *testing.T, r *schema.Resource, state *terraform.InstanceState, meta interface{}) {
+	t.Helper()
+
+	_, diags := r.Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, meta)
+	if diags.HasError() {
+		t.Fatalf("unable to destroy: %v", diags)
+	}
+}
+
+// testFakeCheckNoChanges refreshes the resource of a state and checks that planning its
+// configuration again yields no changes
+func testFakeCheckNoChanges(t *testing.T, r *schema.Resource, state *terraform.InstanceState, config map[string]interface{}, meta interface{}) {
+	t.Helper()
+
+	refreshed, diags := r.RefreshWithoutUpgrade(context.Background(), state, meta)
+	if diags.HasError() {
+		t.Fatalf("unable to refresh: %v", diags)
+	}
+	diff, err := r.Diff(context.Background(), refreshed, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unable to plan: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no changes after apply, got %#v", diff.Attributes)
+	}
+}
+
+// requestCount returns the number of requests received for a method and a path below the data
+// source, such as "GET connectionGroups/ROOT/tree"
+func (f *fakeGuacamole) requestCount(method string, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[fmt.Sprintf("%s session/data/%s/%s", method, fakeGuacamoleDataSource, path)]
+}
+
+func (f *fakeGuacamole) close() {
+	f.server.Close()
+}
+
+func (f *fakeGuacamole) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/") {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+		segments = append(segments, unescaped)
+	}
+	if len(segments) == 2 && segments[0] == "translations" && segments[1] == "en.json" && f.version != "" {
+		f.json(w, http.StatusOK, map[string]interface{}{
+			"APP": map[string]string{"NAME": "Guacamole", "VERSION": f.version},
+		})
+		return
+	}
+	if len(segments) < 2 || segments[0] != "api" {
+		f.error(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+		return
+	}
+	segments = segments[1:]
+	f.requests[fmt.Sprintf("%s %s", r.Method, strings.Join(segments, "/"))]++
+
+	switch {
+	case segments[0] == "tokens":
+		f.serveTokens(w, r, segments[1:])
+	case len(segments) == 1 && segments[0] == "languages" && r.Method == http.MethodGet:
+		f.json(w, http.StatusOK, map[string]string{"en": "English", "de": "Deutsch"})
+	case len(segments) == 1 && segments[0] == "patches" && r.Method == http.MethodGet:
+		f.json(w, http.StatusOK, []string{})
+	case len(segments) >= 4 && segments[0] == "session" && segments[1] == "data":
+		username, ok := f.sessions[r.Header.Get("Guacamole-Token")]
+		if !ok {
+			f.error(w, http.StatusForbidden, "PERMISSION_DENIED", "Permission Denied.")
+			return
+		}
+		if segments[2] != fakeGuacamoleDataSource {
+			f.error(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("No such data source: %s", segments[2]))
+			return
+		}
+		f.serveSessionData(w, r, username, segments[3:])
+	default:
+		f.error(w, http.StatusNotFound, "NOT_FOUND", "Not found")
+	}
+}
+
+func (f *fakeGuacamole) serveTokens(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+
+		token := r.PostForm.Get("token")
+		username, ok := f.sessions[token]
+		if !ok {
+			user, exists := f.users[r.PostForm.Get("username")]
+			if !exists || user.password != r.PostForm.Get("password") {
+				f.error(w, http.StatusForbidden, "INVALID_CREDENTIALS", "Invalid login.")
+				return
+			}
+			username = user.username
+
+			raw := make([]byte, 32)
+			rand.Read(raw)
+			token = strings.ToUpper(hex.EncodeToString(raw))
+			f.sessions[token] = username
+		}
+
+		f.json(w, http.StatusOK, types.AuthenticationResponse{
+			AuthToken:            token,
+			Username:             username,
+			DataSource:           fakeGuacamoleDataSource,
+			AvailableDataSources: []string{fakeGuacamoleDataSource},
+		})
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		if _, ok := f.sessions[segments[0]]; !ok {
+			f.error(w, http.StatusNotFound, "NOT_FOUND", "No such token.")
+			return
+		}
+		delete(f.sessions, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.error(w, http.StatusMethodNotAllowed, "BAD_REQUEST", "Method not allowed")
+	}
+}
+
+func (f *fakeGuacamole) serveSessionData(w http.ResponseWriter, r *http.Request, username string, segments []string) {
+	route := fmt.Sprintf("%s %s", r.Method, segments[0])
+	if len(segments) > 1 {
+		route = fmt.Sprintf("%s/{id}", route)
+	}
+	if len(segments) > 2 {
+		route = fmt.Sprintf("%s/%s", route, strings.Join(segments[2:], "/"))
+	}
+	var id string
+	if len(segments) > 1 {
+		id = segments[1]
+	}
+
+	switch route {
+	case "GET self":
+		f.json(w, http.StatusOK, f.users[username].view())
+	case "GET self/{id}":
+		if id != "effectivePermissions" {
+			f.notFound(w, "self", id)
+			return
+		}
+		f.json(w, http.StatusOK, f.effectivePermissions(username))
+	case "GET schema/{id}":
+		f.serveSchema(w, id)
+
+	case "GET users":
+		users := make(map[string]interface{})
+		for k, u := range f.users {
+			users[k] = u.view()
+		}
+		f.json(w, http.StatusOK, users)
+	case "POST users":
+		f.createUser(w, r)
+	case "GET users/{id}", "PUT users/{id}", "DELETE users/{id}", "GET users/{id}/permissions", "PATCH users/{id}/permissions",
+		"GET users/{id}/userGroups", "PATCH users/{id}/userGroups", "PUT users/{id}/password":
+		user, ok := f.users[id]
+		if !ok {
+			f.notFound(w, "user", id)
+			return
+		}
+		f.serveUser(w, r, username, user, route)
+
+	case "GET userGroups":
+		groups := make(map[string]interface{})
+		for k, g := range f.userGroups {
+			groups[k] = g.view()
+		}
+		f.json(w, http.StatusOK, groups)
+	case "POST userGroups":
+		f.createUserGroup(w, r)
+	case "GET userGroups/{id}", "PUT userGroups/{id}", "DELETE userGroups/{id}",
+		"GET userGroups/{id}/permissions", "PATCH userGroups/{id}/permissions",
+		"GET userGroups/{id}/userGroups", "PATCH userGroups/{id}/userGroups",
+		"GET userGroups/{id}/memberUserGroups", "PATCH userGroups/{id}/memberUserGroups",
+		"GET userGroups/{id}/memberUsers", "PATCH userGroups/{id}/memberUsers":
+		group, ok := f.userGroups[id]
+		if !ok {
+			f.notFound(w, "user group", id)
+			return
+		}
+		f.serveUserGroup(w, r, group, route)
+
+	case "GET connections":
+		connections := make(map[string]interface{})
+		for k, c := range f.connections {
+			connections[k] = c.view()
+		}
+		f.json(w, http.StatusOK, connections)
+	case "POST connections":
+		f.writeConnection(w, r, nil)
+	case "GET connections/{id}", "PUT connections/{id}", "DELETE connections/{id}", "GET connections/{id}/parameters":
+		connection, ok := f.connections[id]
+		if !ok {
+			f.notFound(w, "connection", id)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if strings.HasSuffix(route, "/parameters") {
+				f.json(w, http.StatusOK, connection.parameters)
+				return
+			}
+			f.json(w, http.StatusOK, connection.view())
+		case http.MethodPut:
+			f.writeConnection(w, r, connection)
+		case http.MethodDelete:
+			f.deleteConnection(id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	case "GET connectionGroups":
+		groups := make(map[string]interface{})
+		for k, g := range f.connectionGroups {
+			groups[k] = g.view()
+		}
+		f.json(w, http.StatusOK, groups)
+	case "POST connectionGroups":
+		f.writeConnectionGroup(w, r, nil)
+	case "GET connectionGroups/{id}", "PUT connectionGroups/{id}", "DELETE connectionGroups/{id}", "GET connectionGroups/{id}/tree":
+		if id == "ROOT" {
+			switch route {
+			case "GET connectionGroups/{id}":
+				f.json(w, http.StatusOK, f.rootConnectionGroup().view())
+			case "GET connectionGroups/{id}/tree":
+				f.json(w, http.StatusOK, f.connectionTree(f.rootConnectionGroup()))
+			default:
+				f.error(w, http.StatusBadRequest, "BAD_REQUEST", "The root connection group cannot be modified.")
+			}
+			return
+		}
+		group, ok := f.connectionGroups[id]
+		if !ok {
+			f.notFound(w, "connection group", id)
+			return
+		}
+		switch route {
+		case "GET connectionGroups/{id}":
+			f.json(w, http.StatusOK, group.view())
+		case "GET connectionGroups/{id}/tree":
+			f.json(w, http.StatusOK, f.connectionTree(group))
+		case "PUT connectionGroups/{id}":
+			f.writeConnectionGroup(w, r, group)
+		case "DELETE connectionGroups/{id}":
+			f.deleteConnectionGroup(id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	default:
+		f.error(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("No such resource: %s", strings.Join(segments, "/")))
+	}
+}
+
+func (f *fakeGuacamole) serveSchema(w http.ResponseWriter, name string) {
+	if name == "protocols" {
+		f.json(w, http.StatusOK, json.RawMessage(fakeProtocolSchemas))
+		return
+	}
+	forms, ok := fakeAttributeSchemas[strings.TrimSuffix(name, "Attributes")]
+	if !ok || !strings.HasSuffix(name, "Attributes") {
+		f.notFound(w, "schema", name)
+		return
+	}
+	if name == "userAttributes" && f.totp {
+		forms = append(append([]types.ConnectionForm{}, forms...), types.ConnectionForm{
+			Name: "totp-enrollment",
+			Fields: []types.ConnectionFormField{
+				{Name: totpKeySecretAttribute, Type: "GENERATED_TOTP_KEY"},
+				{Name: totpKeyConfirmedAttribute, Type: "BOOLEAN", Options: []string{"true"}},
+			},
+		})
+	}
+	f.json(w, http.StatusOK, forms)
+}
+
+// installTOTP adds the user attributes of the TOTP extension
+func (f *fakeGuacamole) installTOTP() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.totp = true
+}
+
+func (f *fakeGuacamole) createUser(w http.ResponseWriter, r *http.Request) {
+	var body fakeObject
+	if !f.decode(w, r, &body) {
+		return
+	}
+	if body.Username == "" {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", "The username must not be blank.")
+		return
+	}
+	if _, ok := f.users[body.Username]; ok {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("User %q already exists.", body.Username))
+		return
+	}
+
+	user := &fakeUser{
+		username:    body.Username,
+		password:    body.Password,
+		attributes:  make(map[string]*string),
+		permissions: newFakePermissions(),
+	}
+	setFakeAttributes(user.attributes, body.Attributes)
+	f.users[user.username] = user
+
+	f.json(w, http.StatusOK, user.view())
+}
+
+func (f *fakeGuacamole) serveUser(w http.ResponseWriter, r *http.Request, session string, user *fakeUser, route string) {
+	switch route {
+	case "GET users/{id}":
+		f.json(w, http.StatusOK, user.view())
+	case "PUT users/{id}":
+		var body fakeObject
+		if !f.decode(w, r, &body) {
+			return
+		}
+		if body.Password != "" {
+			user.password = body.Password
+		}
+		setFakeAttributes(user.attributes, body.Attributes)
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE users/{id}":
+		delete(f.users, user.username)
+		for _, group := range f.userGroups {
+			delete(group.memberUsers, user.username)
+		}
+		f.revokeObjectPermissions("userPermissions", user.username)
+		w.WriteHeader(http.StatusNoContent)
+	case "GET users/{id}/permissions":
+		f.json(w, http.StatusOK, user.permissions.view())
+	case "PATCH users/{id}/permissions":
+		f.patchPermissions(w, r, user.permissions)
+	case "GET users/{id}/userGroups":
+		var groups []string
+		for identifier, group := range f.userGroups {
+			if group.memberUsers[user.username] {
+				groups = append(groups, identifier)
+			}
+		}
+		sort.Strings(groups)
+		f.json(w, http.StatusOK, nonNilStrings(groups))
+	case "PATCH users/{id}/userGroups":
+		f.patchMembership(w, r, func(op string, identifier string) error {
+			group, ok := f.userGroups[identifier]
+			if !ok {
+				return fmt.Errorf("no such user group: %s", identifier)
+			}
+			setMember(group.memberUsers, user.username, op)
+			return nil
+		})
+	case "PUT users/{id}/password":
+		var body struct {
+			OldPassword string `json:"oldPassword"`
+			NewPassword string `json:"newPassword"`
+		}
+		if !f.decode(w, r, &body) {
+			return
+		}
+		if session != user.username {
+			f.error(w, http.StatusForbidden, "PERMISSION_DENIED", "Permission denied.")
+			return
+		}
+		if body.OldPassword != user.password {
+			f.error(w, http.StatusForbidden, "PERMISSION_DENIED", "Permission denied.")
+			return
+		}
+		user.password = body.NewPassword
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeGuacamole) createUserGroup(w http.ResponseWriter, r *http.Request) {
+	var body fakeObject
+	if !f.decode(w, r, &body) {
+		return
+	}
+	if body.Identifier == "" {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", "The group name must not be blank.")
+		return
+	}
+	if _, ok := f.userGroups[body.Identifier]; ok {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Group %q already exists.", body.Identifier))
+		return
+	}
+
+	group := &fakeUserGroup{
+		identifier:   body.Identifier,
+		attributes:   make(map[string]*string),
+		permissions:  newFakePermissions(),
+		memberUsers:  make(map[string]bool),
+		memberGroups: make(map[string]bool),
+	}
+	setFakeAttributes(group.attributes, body.Attributes)
+	f.userGroups[group.identifier] = group
+
+	f.json(w, http.StatusOK, group.view())
+}
+
+func (f *fakeGuacamole) serveUserGroup(w http.ResponseWriter, r *http.Request, group *fakeUserGroup, route string) {
+	switch route {
+	case "GET userGroups/{id}":
+		f.json(w, http.StatusOK, group.view())
+	case "PUT userGroups/{id}":
+		var body fakeObject
+		if !f.decode(w, r, &body) {
+			return
+		}
+		setFakeAttributes(group.attributes, body.Attributes)
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE userGroups/{id}":
+		delete(f.userGroups, group.identifier)
+		for _, parent := range f.userGroups {
+			delete(parent.memberGroups, group.identifier)
+		}
+		f.revokeObjectPermissions("userGroupPermissions", group.identifier)
+		w.WriteHeader(http.StatusNoContent)
+	case "GET userGroups/{id}/permissions":
+		f.json(w, http.StatusOK, group.permissions.view())
+	case "PATCH userGroups/{id}/permissions":
+		f.patchPermissions(w, r, group.permissions)
+	case "GET userGroups/{id}/userGroups":
+		var parents []string
+		for identifier, parent := range f.userGroups {
+			if parent.memberGroups[group.identifier] {
+				parents = append(parents, identifier)
+			}
+		}
+		sort.Strings(parents)
+		f.json(w, http.StatusOK, nonNilStrings(parents))
+	case "PATCH userGroups/{id}/userGroups":
+		f.patchMembership(w, r, func(op string, identifier string) error {
+			parent, ok := f.userGroups[identifier]
+			if !ok {
+				return fmt.Errorf("no such user group: %s", identifier)
+			}
+			setMember(parent.memberGroups, group.identifier, op)
+			return nil
+		})
+	case "GET userGroups/{id}/memberUserGroups":
+		f.json(w, http.StatusOK, sortedKeys(group.memberGroups))
+	case "PATCH userGroups/{id}/memberUserGroups":
+		f.patchMembership(w, r, func(op string, identifier string) error {
+			if _, ok := f.userGroups[identifier]; !ok {
+				return fmt.Errorf("no such user group: %s", identifier)
+			}
+			setMember(group.memberGroups, identifier, op)
+			return nil
+		})
+	case "GET userGroups/{id}/memberUsers":
+		f.json(w, http.StatusOK, sortedKeys(group.memberUsers))
+	case "PATCH userGroups/{id}/memberUsers":
+		f.patchMembership(w, r, func(op string, identifier string) error {
+			if _, ok := f.users[identifier]; !ok {
+				return fmt.Errorf("no such user: %s", identifier)
+			}
+			setMember(group.memberUsers, identifier, op)
+			return nil
+		})
+	}
+}
+
+// writeConnection creates a connection, or updates it if it is not nil.  Like guacamole, the
+// parameters of the request replace the stored ones and blank parameters aren't stored
+func (f *fakeGuacamole) writeConnection(w http.ResponseWriter, r *http.Request, connection *fakeConnection) {
+	var body fakeObject
+	if !f.decode(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", "Connection names must not be blank.")
+		return
+	}
+	if body.ParentIdentifier == "" {
+		body.ParentIdentifier = "ROOT"
+	}
+	if !f.connectionGroupExists(body.ParentIdentifier) {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("No such connection group: %s", body.ParentIdentifier))
+		return
+	}
+	for _, other := range f.connections {
+		if other != connection && other.parentIdentifier == body.ParentIdentifier && other.name == body.Name {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("The connection %q already exists.", body.Name))
+			return
+		}
+	}
+
+	if connection == nil {
+		f.nextConnection++
+		connection = &fakeConnection{
+			identifier: strconv.Itoa(f.nextConnection),
+			attributes: make(map[string]*string),
+		}
+		f.connections[connection.identifier] = connection
+	}
+	connection.name = body.Name
+	connection.parentIdentifier = body.ParentIdentifier
+	connection.protocol = body.Protocol
+	setFakeAttributes(connection.attributes, body.Attributes)
+	if body.Parameters != nil {
+		connection.parameters = make(map[string]string)
+		for k, v := range body.Parameters {
+			if v != nil && *v != "" {
+				connection.parameters[k] = *v
+			}
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		f.json(w, http.StatusOK, connection.view())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeGuacamole) deleteConnection(identifier string) {
+	delete(f.connections, identifier)
+	f.revokeObjectPermissions("connectionPermissions", identifier)
+}
+
+// writeConnectionGroup creates a connection group, or updates it if it is not nil
+func (f *fakeGuacamole) writeConnectionGroup(w http.ResponseWriter, r *http.Request, group *fakeConnectionGroup) {
+	var body fakeObject
+	if !f.decode(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", "Connection group names must not be blank.")
+		return
+	}
+	if body.ParentIdentifier == "" {
+		body.ParentIdentifier = "ROOT"
+	}
+	if !f.connectionGroupExists(body.ParentIdentifier) {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("No such connection group: %s", body.ParentIdentifier))
+		return
+	}
+	if body.Type != "ORGANIZATIONAL" && body.Type != "BALANCING" {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid connection group type: %s", body.Type))
+		return
+	}
+	if group != nil {
+		// a group can't be moved below itself
+		for parent := body.ParentIdentifier; parent != "ROOT"; parent = f.connectionGroups[parent].parentIdentifier {
+			if parent == group.identifier {
+				f.error(w, http.StatusBadRequest, "BAD_REQUEST", "A connection group cannot be its own ancestor.")
+				return
+			}
+		}
+	}
+
+	if group == nil {
+		f.nextGroup++
+		group = &fakeConnectionGroup{
+			identifier: strconv.Itoa(f.nextGroup),
+			attributes: make(map[string]*string),
+		}
+		f.connectionGroups[group.identifier] = group
+	}
+	group.name = body.Name
+	group.parentIdentifier = body.ParentIdentifier
+	group.groupType = body.Type
+	setFakeAttributes(group.attributes, body.Attributes)
+
+	if r.Method == http.MethodPost {
+		f.json(w, http.StatusOK, group.view())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteConnectionGroup deletes a connection group along with its descendants
+func (f *fakeGuacamole) deleteConnectionGroup(identifier string) {
+	for id, connection := range f.connections {
+		if connection.parentIdentifier == identifier {
+			f.deleteConnection(id)
+		}
+	}
+	for id, group := range f.connectionGroups {
+		if group.parentIdentifier == identifier {
+			f.deleteConnectionGroup(id)
+		}
+	}
+	delete(f.connectionGroups, identifier)
+	f.revokeObjectPermissions("connectionGroupPermissions", identifier)
+}
+
+func (f *fakeGuacamole) connectionGroupExists(identifier string) bool {
+	_, ok := f.connectionGroups[identifier]
+	return ok || identifier == "ROOT"
+}
+
+func (f *fakeGuacamole) rootConnectionGroup() *fakeConnectionGroup {
+	return &fakeConnectionGroup{
+		identifier: "ROOT",
+		name:       "ROOT",
+		groupType:  "ORGANIZATIONAL",
+		attributes: make(map[string]*string),
+	}
+}
+
+// connectionTree returns a connection group with its descendants, leaving out empty child lists
+// like guacamole does
+func (f *fakeGuacamole) connectionTree(group *fakeConnectionGroup) map[string]interface{} {
+	tree := group.view()
+
+	var connectionIDs, groupIDs []string
+	for id, connection := range f.connections {
+		if connection.parentIdentifier == group.identifier {
+			connectionIDs = append(connectionIDs, id)
+		}
+	}
+	for id, child := range f.connectionGroups {
+		if child.parentIdentifier == group.identifier {
+			groupIDs = append(groupIDs, id)
+		}
+	}
+	sort.Strings(connectionIDs)
+	sort.Strings(groupIDs)
+
+	if len(connectionIDs) > 0 {
+		var connections []interface{}
+		for _, id := range connectionIDs {
+			connections = append(connections, f.connections[id].view())
+		}
+		tree["childConnections"] = connections
+	}
+	if len(groupIDs) > 0 {
+		var groups []interface{}
+		for _, id := range groupIDs {
+			groups = append(groups, f.connectionTree(f.connectionGroups[id]))
+		}
+		tree["childConnectionGroups"] = groups
+	}
+
+	return tree
+}
+
+// patchPermissions applies a json patch of permissions such as
+// {"op": "add", "path": "/connectionPermissions/1", "value": "READ"}
+func (f *fakeGuacamole) patchPermissions(w http.ResponseWriter, r *http.Request, permissions *fakePermissions) {
+	var patch []types.GuacPermissionItem
+	if !f.decode(w, r, &patch) {
+		return
+	}
+
+	for _, item := range patch {
+		if item.Op != "add" && item.Op != "remove" {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Unsupported patch operation: %s", item.Op))
+			return
+		}
+		path := strings.SplitN(strings.TrimPrefix(item.Path, "/"), "/", 2)
+		if path[0] == "systemPermissions" && len(path) == 1 {
+			setMember(permissions.system, item.Value, item.Op)
+			continue
+		}
+		if len(path) != 2 || !stringSliceContains(fakeObjectPermissionTypes, path[0]) {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Unsupported patch path: %s", item.Path))
+			return
+		}
+		identifier := strings.NewReplacer("~1", "/", "~0", "~").Replace(path[1])
+		if permissions.objects[path[0]] == nil {
+			permissions.objects[path[0]] = make(map[string]map[string]bool)
+		}
+		if permissions.objects[path[0]][identifier] == nil {
+			permissions.objects[path[0]][identifier] = make(map[string]bool)
+		}
+		setMember(permissions.objects[path[0]][identifier], item.Value, item.Op)
+		if len(permissions.objects[path[0]][identifier]) == 0 {
+			delete(permissions.objects[path[0]], identifier)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// fakeObjectPermissionTypes lists the object permission types of guacamole permission sets
+var fakeObjectPermissionTypes = []string{
+	"connectionPermissions",
+	"connectionGroupPermissions",
+	"sharingProfilePermissions",
+	"activeConnectionPermissions",
+	"userPermissions",
+	"userGroupPermissions",
+}
+
+// patchMembership applies a json patch of group memberships such as
+// {"op": "add", "path": "/", "value": "group"}
+func (f *fakeGuacamole) patchMembership(w http.ResponseWriter, r *http.Request, apply func(op string, identifier string) error) {
+	var patch []types.GuacPermissionItem
+	if !f.decode(w, r, &patch) {
+		return
+	}
+
+	for _, item := range patch {
+		if (item.Op != "add" && item.Op != "remove") || item.Path != "/" {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Unsupported patch operation: %s %s", item.Op, item.Path))
+			return
+		}
+		if err := apply(item.Op, item.Value); err != nil {
+			f.error(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeObjectPermissions removes the permissions granted on a deleted object
+func (f *fakeGuacamole) revokeObjectPermissions(permissionType string, identifier string) {
+	for _, user := range f.users {
+		delete(user.permissions.objects[permissionType], identifier)
+	}
+	for _, group := range f.userGroups {
+		delete(group.permissions.objects[permissionType], identifier)
+	}
+}
+
+// effectivePermissions returns the permissions of a user combined with those of its groups
+func (f *fakeGuacamole) effectivePermissions(username string) types.GuacPermissionData {
+	combined := newFakePermissions()
+	sets := []*fakePermissions{f.users[username].permissions}
+	for _, group := range f.userGroups {
+		if group.memberUsers[username] {
+			sets = append(sets, group.permissions)
+		}
+	}
+	for _, set := range sets {
+		for p := range set.system {
+			combined.system[p] = true
+		}
+		for kind, objects := range set.objects {
+			if combined.objects[kind] == nil {
+				combined.objects[kind] = make(map[string]map[string]bool)
+			}
+			for id, granted := range objects {
+				if combined.objects[kind][id] == nil {
+					combined.objects[kind][id] = make(map[string]bool)
+				}
+				for p := range granted {
+					combined.objects[kind][id][p] = true
+				}
+			}
+		}
+	}
+	return combined.view()
+}
+
+func (p *fakePermissions) view() types.GuacPermissionData {
+	objects := func(kind string) map[string][]string {
+		view := make(map[string][]string)
+		for id, granted := range p.objects[kind] {
+			view[id] = sortedKeys(granted)
+		}
+		return view
+	}
+	return types.GuacPermissionData{
+		ConnectionPermissions:       objects("connectionPermissions"),
+		ConnectionGroupPermissions:  objects("connectionGroupPermissions"),
+		SharingProfilePermissions:   objects("sharingProfilePermissions"),
+		ActiveConnectionPermissions: objects("activeConnectionPermissions"),
+		UserPermissions:             objects("userPermissions"),
+		UserGroupPermissions:        objects("userGroupPermissions"),
+		SystemPermissions:           sortedKeys(p.system),
+	}
+}
+
+func (u *fakeUser) view() map[string]interface{} {
+	return map[string]interface{}{
+		"username":   u.username,
+		"attributes": viewFakeAttributes(u.attributes, "user"),
+		"lastActive": 0,
+	}
+}
+
+func (g *fakeUserGroup) view() map[string]interface{} {
+	return map[string]interface{}{
+		"identifier": g.identifier,
+		"attributes": viewFakeAttributes(g.attributes, "userGroup"),
+	}
+}
+
+func (c *fakeConnection) view() map[string]interface{} {
+	return map[string]interface{}{
+		"name":              c.name,
+		"identifier":        c.identifier,
+		"parentIdentifier":  c.parentIdentifier,
+		"protocol":          c.protocol,
+		"attributes":        viewFakeAttributes(c.attributes, "connection"),
+		"activeConnections": 0,
+	}
+}
+
+func (g *fakeConnectionGroup) view() map[string]interface{} {
+	view := map[string]interface{}{
+		"name":              g.name,
+		"identifier":        g.identifier,
+		"type":              g.groupType,
+		"attributes":        viewFakeAttributes(g.attributes, "connectionGroup"),
+		"activeConnections": 0,
+	}
+	if g.identifier != "ROOT" {
+		view["parentIdentifier"] = g.parentIdentifier
+	}
+	return view
+}
+
+// setFakeAttributes stores the attributes of a request, blank and null values unsetting them
+func setFakeAttributes(stored map[string]*string, attributes map[string]*string) {
+	for k, v := range attributes {
+		if v == nil || *v == "" {
+			delete(stored, k)
+			continue
+		}
+		value := *v
+		stored[k] = &value
+	}
+}
+
+// viewFakeAttributes returns stored attributes along with the unset attributes of the object
+// type schema, which guacamole returns as null
+func viewFakeAttributes(stored map[string]*string, objectType string) map[string]*string {
+	view := make(map[string]*string)
+	for _, form := range fakeAttributeSchemas[objectType] {
+		for _, field := range form.Fields {
+			view[field.Name] = nil
+		}
+	}
+	for k, v := range stored {
+		view[k] = v
+	}
+	return view
+}
+
+func setMember(set map[string]bool, member string, op string) {
+	if op == "add" {
+		set[member] = true
+	} else {
+		delete(set, member)
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := []string{}
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func (f *fakeGuacamole) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		f.error(w, http.StatusBadRequest, "BAD_REQUEST", fmt.Sprintf("Invalid request body: %s", err))
+		return false
+	}
+	return true
+}
+
+func (f *fakeGuacamole) json(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeGuacamole) error(w http.ResponseWriter, status int, errorType string, message string) {
+	f.json(w, status, fakeGuacamoleError{Message: message, Type: errorType})
+}
+
+func (f *fakeGuacamole) notFound(w http.ResponseWriter, objectType string, identifier string) {
+	f.error(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("No such %s: %q", objectType, identifier))
+}
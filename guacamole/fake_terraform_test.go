@@ -0,0 +1,285 @@
+package guacamole
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testFakeTestCase runs the steps of an acceptance test in process against a fake guacamole, the
+// way terraform runs them: the resources of each step's configuration are applied in dependency
+// order, planned again expecting no changes and checked, and the resources left out of the
+// configuration are destroyed.  Every resource is destroyed at the end, before CheckDestroy
+func testFakeTestCase(t *testing.T, c resource.TestCase) {
+	t.Helper()
+
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+	f.setenv(t)
+
+	if c.PreCheck != nil {
+		c.PreCheck()
+	}
+
+	provider := c.Providers["guacamole"]
+	if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+		t.Fatalf("unable to configure the provider: %v", diags)
+	}
+
+	run := &testFakeRun{
+		provider: provider,
+		state:    terraform.NewState(),
+	}
+	for i, step := range c.Steps {
+		if step.ImportState || step.PlanOnly || step.ExpectError != nil || step.ExpectNonEmptyPlan || step.Config == "" {
+			t.Fatalf("step %d: only steps applying a configuration run against the fake guacamole", i+1)
+		}
+
+		run.apply(t, testHCLParse(t, "main.tf", step.Config))
+
+		if step.Check != nil {
+			if err := step.Check(run.shimmedState(t)); err != nil {
+				t.Fatalf("step %d: %s", i+1, err)
+			}
+		}
+	}
+
+	statePreDestroy := run.shimmedState(t)
+	run.apply(t, &hclsyntax.Body{})
+
+	if c.CheckDestroy != nil {
+		if err := c.CheckDestroy(statePreDestroy); err != nil {
+			t.Fatalf("check destroy: %s", err)
+		}
+	}
+}
+
+// testFakeRun holds the state of an acceptance test run in process, along with the order the
+// resources were created in to destroy them in reverse
+type testFakeRun struct {
+	provider *schema.Provider
+	state    *terraform.State
+	order    []string
+}
+
+// apply applies the resource blocks of a configuration, each one once the resources it refers to
+// are applied, then destroys the resources missing from the configuration
+func (run *testFakeRun) apply(t *testing.T, body *hclsyntax.Body) {
+	t.Helper()
+
+	resources := run.state.RootModule().Resources
+
+	var pending []*hclsyntax.Block
+	configured := make(map[string]bool)
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "provider":
+		case "resource":
+			pending = append(pending, block)
+			configured[block.Labels[0]+"."+block.Labels[1]] = true
+		default:
+			t.Fatalf("%s blocks don't run against the fake guacamole", block.Type)
+		}
+	}
+
+	for len(pending) > 0 {
+		var block *hclsyntax.Block
+		block, pending = run.next(t, pending)
+		address := block.Labels[0] + "." + block.Labels[1]
+
+		r := run.provider.ResourcesMap[block.Labels[0]]
+		if r == nil {
+			t.Fatalf("unknown resource type %s", block.Labels[0])
+		}
+
+		var previous *terraform.InstanceState
+		if rs, ok := resources[address]; ok {
+			previous = rs.Primary
+		} else {
+			run.order = append(run.order, address)
+		}
+
+		resources[address] = &terraform.ResourceState{
+			Type:    block.Labels[0],
+			Primary: testFakeApply(t, r, previous, testHCLConfig(t, block.Body, run.evalContext()), run.provider.Meta()),
+		}
+	}
+
+	for i := len(run.order) - 1; i >= 0; i-- {
+		address := run.order[i]
+		if configured[address] {
+			continue
+		}
+		rs := resources[address]
+		testFakeDestroy(t, run.provider.ResourcesMap[rs.Type], rs.Primary, run.provider.Meta())
+		delete(resources, address)
+		run.order = append(run.order[:i], run.order[i+1:]...)
+	}
+}
+
+// shimmedState returns the state the way terraform hands it to checks, with the elements of sets
+// flattened by their position rather than their hash
+func (run *testFakeRun) shimmedState(t *testing.T) *terraform.State {
+	t.Helper()
+
+	state := run.state.DeepCopy()
+	for address, rs := range state.RootModule().Resources {
+		r := run.provider.ResourcesMap[rs.Type]
+		value, err := rs.Primary.AttrsAsObjectValue(r.CoreConfigSchema().ImpliedType())
+		if err != nil {
+			t.Fatalf("unable to shim the state of %s: %s", address, err)
+		}
+		rs.Primary = terraform.NewInstanceStateShimmedFromValue(value, r.SchemaVersion)
+	}
+	return state
+}
+
+// next returns the first pending resource block whose references are all applied, along with
+// the blocks still pending
+func (run *testFakeRun) next(t *testing.T, pending []*hclsyntax.Block) (*hclsyntax.Block, []*hclsyntax.Block) {
+	t.Helper()
+
+	resources := run.state.RootModule().Resources
+	for i, block := range pending {
+		applied := true
+		for reference := range testHCLReferences(block.Body) {
+			if _, ok := resources[reference]; !ok {
+				applied = false
+				break
+			}
+		}
+		if applied {
+			return block, append(pending[:i:i], pending[i+1:]...)
+		}
+	}
+
+	var addresses []string
+	for _, block := range pending {
+		addresses = append(addresses, block.Labels[0]+"."+block.Labels[1])
+	}
+	t.Fatalf("unable to resolve the references of %s", strings.Join(addresses, ", "))
+	return nil, nil
+}
+
+// evalContext resolves references to the top level attributes of the applied resources
+func (run *testFakeRun) evalContext() *hcl.EvalContext {
+	resources := make(map[string]map[string]string)
+	for address, rs := range run.state.RootModule().Resources {
+		attributes := map[string]string{"id": rs.Primary.ID}
+		for k, v := range rs.Primary.Attributes {
+			if !strings.Contains(k, ".") {
+				attributes[k] = v
+			}
+		}
+		resources[address] = attributes
+	}
+	return testHCLEvalContext(resources, nil)
+}
+
+func testHCLParse(t *testing.T, name string, content string) *hclsyntax.Body {
+	t.Helper()
+
+	file, diags := hclsyntax.ParseConfig([]byte(content), name, hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("unable to parse %s: %s\n%s", name, diags.Error(), content)
+	}
+	return file.Body.(*hclsyntax.Body)
+}
+
+// testHCLReferences returns the addresses of the resources a body refers to
+func testHCLReferences(body *hclsyntax.Body) map[string]bool {
+	references := make(map[string]bool)
+	for _, attribute := range body.Attributes {
+		for _, traversal := range attribute.Expr.Variables() {
+			if traversal.RootName() == "var" || len(traversal) < 2 {
+				continue
+			}
+			if name, ok := traversal[1].(hcl.TraverseAttr); ok {
+				references[traversal.RootName()+"."+name.Name] = true
+			}
+		}
+	}
+	for _, block := range body.Blocks {
+		for reference := range testHCLReferences(block.Body) {
+			references[reference] = true
+		}
+	}
+	return references
+}
+
+// testHCLEvalContext resolves variables and the attributes of resources, by resource address, a
+// configuration refers to
+func testHCLEvalContext(resources map[string]map[string]string, variables map[string]cty.Value) *hcl.EvalContext {
+	types := make(map[string]map[string]cty.Value)
+	for address, attributes := range resources {
+		parts := strings.SplitN(address, ".", 2)
+		if types[parts[0]] == nil {
+			types[parts[0]] = make(map[string]cty.Value)
+		}
+		values := make(map[string]cty.Value, len(attributes))
+		for k, v := range attributes {
+			values[k] = cty.StringVal(v)
+		}
+		types[parts[0]][parts[1]] = cty.ObjectVal(values)
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+		},
+	}
+	for resourceType, labels := range types {
+		ctx.Variables[resourceType] = cty.ObjectVal(labels)
+	}
+	return ctx
+}
+
+// testHCLConfig evaluates a resource body into the raw configuration of the resource
+func testHCLConfig(t *testing.T, body *hclsyntax.Body, ctx *hcl.EvalContext) map[string]interface{} {
+	t.Helper()
+
+	config := make(map[string]interface{})
+	for name, attribute := range body.Attributes {
+		value, diags := attribute.Expr.Value(ctx)
+		if diags.HasErrors() {
+			t.Fatalf("unable to evaluate %s: %s", name, diags.Error())
+		}
+		config[name] = testHCLValue(value)
+	}
+	for _, block := range body.Blocks {
+		list, _ := config[block.Type].([]interface{})
+		config[block.Type] = append(list, testHCLConfig(t, block.Body, ctx))
+	}
+	return config
+}
+
+func testHCLValue(value cty.Value) interface{} {
+	switch {
+	case value.Type() == cty.String:
+		return value.AsString()
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type() == cty.Number:
+		i, _ := value.AsBigFloat().Int64()
+		return int(i)
+	case value.Type().IsObjectType() || value.Type().IsMapType():
+		values := make(map[string]interface{})
+		for k, v := range value.AsValueMap() {
+			values[k] = testHCLValue(v)
+		}
+		return values
+	default:
+		var values []interface{}
+		for _, v := range value.AsValueSlice() {
+			values = append(values, testHCLValue(v))
+		}
+		return values
+	}
+}
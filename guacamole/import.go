@@ -0,0 +1,75 @@
+package guacamole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/techBeck03/guacamole-api-client/types"
+)
+
+// importPathPrefix marks import ids that are paths ("Parent/Name") rather than numeric identifiers
+const importPathPrefix = "path:"
+
+// importConnectionState returns an import function that accepts a numeric identifier or a
+// "path:Parent/Name" id and rejects connections of another protocol
+func importConnectionState(protocol string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		client := m.(*guacamoleClient)
+
+		var connection types.GuacConnection
+		var err error
+		if strings.HasPrefix(d.Id(), importPathPrefix) {
+			connection, err = client.ReadConnectionByPath(strings.TrimPrefix(d.Id(), importPathPrefix))
+		} else {
+			connection, err = client.ReadConnection(d.Id())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if connection.Identifier == "" {
+			return nil, fmt.Errorf("no connection found with identifier: %s", d.Id())
+		}
+
+		if connection.Protocol != protocol {
+			return nil, fmt.Errorf("connection %s uses the %s protocol and cannot be imported as a %s connection", d.Id(), connection.Protocol, protocol)
+		}
+
+		d.SetId(connection.Identifier)
+		// defaults aren't set on import, leaving the first plan to change them otherwise
+		d.Set("secret_state_mode", secretStateModePlaintext)
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// importConnectionGroupState imports a connection group by numeric identifier or "path:Parent/Name" id
+func importConnectionGroupState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*guacamoleClient)
+
+	if !strings.HasPrefix(d.Id(), importPathPrefix) {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	group, err := client.ReadConnectionGroupByPath(strings.TrimPrefix(d.Id(), importPathPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(group.Identifier)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// importUserState imports a user by username, setting the defaults of the password generation
+// arguments
+func importUserState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// defaults aren't set on import, leaving the first plan to change them otherwise
+	d.Set("generate_password", false)
+	d.Set("password_length", defaultPasswordLength)
+	d.Set("password_charset", defaultPasswordCharset)
+
+	return []*schema.ResourceData{d}, nil
+}
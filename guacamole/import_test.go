@@ -0,0 +1,127 @@
+package guacamole
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestImportConnectionStateFakeProtocol(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+
+	ssh := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name":              "web",
+		"parent_identifier": "ROOT",
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "web.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "web",
+			},
+		},
+	}, client)
+
+	imported, err := testImportState(guacamoleConnectionSSH(), ssh.ID, client)
+	if err != nil {
+		t.Fatalf("unable to import ssh connection: %s", err)
+	}
+	if imported.Id() != ssh.ID || imported.Get("secret_state_mode").(string) != secretStateModePlaintext {
+		t.Fatalf("expected ssh connection %s to be imported with the default secret_state_mode, got %q and %q", ssh.ID, imported.Id(), imported.Get("secret_state_mode"))
+	}
+
+	others := map[string]*schema.Resource{
+		"rdp":        guacamoleConnectionRDP(),
+		"vnc":        guacamoleConnectionVNC(),
+		"telnet":     guacamoleConnectionTelnet(),
+		"kubernetes": guacamoleConnectionKubernetes(),
+	}
+	for protocol, r := range others {
+		_, err := testImportState(r, ssh.ID, client)
+		expected := "uses the ssh protocol and cannot be imported as a " + protocol + " connection"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected importing the ssh connection as %s to fail with %q, got %v", protocol, expected, err)
+		}
+	}
+
+	if _, err := testImportState(guacamoleConnectionSSH(), "404", client); err == nil {
+		t.Errorf("expected importing a missing connection to fail")
+	}
+}
+
+// testImportState runs the importer of a resource for an import id
+func testImportState(r *schema.Resource, id string, meta interface{}) (*schema.ResourceData, error) {
+	imported, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		return nil, err
+	}
+	return imported[0], nil
+}
+
+func TestImportFakePath(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+
+	prod := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "Prod",
+		"parent_identifier": "ROOT",
+	}, client)
+	database := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "DB",
+		"parent_identifier": prod.ID,
+	}, client)
+	connections := make(map[string]string)
+	for path, parent := range map[string]string{"Prod/DB/pg": database.ID, "pg": "ROOT"} {
+		state := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+			"name":              "pg",
+			"parent_identifier": parent,
+			"network": []interface{}{
+				map[string]interface{}{
+					"hostname": "pg.example.com",
+				},
+			},
+			"authentication": []interface{}{
+				map[string]interface{}{
+					"username": "postgres",
+				},
+			},
+		}, client)
+		connections[path] = state.ID
+	}
+
+	// connections of the same name resolve by their full path
+	for path, id := range connections {
+		imported, err := testImportState(guacamoleConnectionSSH(), importPathPrefix+path, client)
+		if err != nil {
+			t.Fatalf("unable to import connection %s: %s", path, err)
+		}
+		if imported.Id() != id {
+			t.Errorf("expected path %s to import connection %s, got %q", path, id, imported.Id())
+		}
+	}
+
+	for path, id := range map[string]string{"Prod": prod.ID, "Prod/DB": database.ID} {
+		imported, err := testImportState(guacamoleConnectionGroup(), importPathPrefix+path, client)
+		if err != nil {
+			t.Fatalf("unable to import connection group %s: %s", path, err)
+		}
+		if imported.Id() != id {
+			t.Errorf("expected path %s to import connection group %s, got %q", path, id, imported.Id())
+		}
+	}
+	imported, err := testImportState(guacamoleConnectionGroup(), database.ID, client)
+	if err != nil || imported.Id() != database.ID {
+		t.Errorf("expected a numeric identifier to import connection group %s, got %v", database.ID, err)
+	}
+
+	if _, err := testImportState(guacamoleConnectionSSH(), importPathPrefix+"Prod/pg", client); err == nil || !strings.Contains(err.Error(), "no connection found with path: Prod/pg") {
+		t.Errorf("expected an unknown connection path to fail, got %v", err)
+	}
+	if _, err := testImportState(guacamoleConnectionGroup(), importPathPrefix+"DB", client); err == nil || !strings.Contains(err.Error(), "no connection group found with path: DB") {
+		t.Errorf("expected a connection group path missing its parent to fail, got %v", err)
+	}
+}
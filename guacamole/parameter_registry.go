@@ -0,0 +1,472 @@
+package guacamole
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// Value types of connection parameters.  Lists are space separated by guacd unless comma
+// separated, color schemes are managed by a color_scheme preset and a custom_color_scheme block
+const (
+	guacdParameterString = iota
+	guacdParameterBool
+	guacdParameterInt
+	guacdParameterList
+	guacdParameterCommaList
+	guacdParameterColorScheme
+)
+
+// connectionParameter describes a guacd connection parameter and the field managing it.  The
+// connection resource and data source schemas, their validation and the conversion from and to
+// guacamole connections are derived from these descriptions
+type connectionParameter struct {
+	// name is the guacd parameter name, which is also its json name in the guacamole api
+	name string
+	// field is the name of the field in the parameters block
+	field string
+	// section is the section block holding the field
+	section string
+	// sectionField overrides the name of the field in its section block, which otherwise is the
+	// field name without the section prefix
+	sectionField string
+	valueType    int
+	description  string
+	// options lists the valid values of a string parameter
+	options   []string
+	sensitive bool
+	required  bool
+	// defaultValue is the value guacd uses when the parameter is unset
+	defaultValue string
+	validate     schema.SchemaValidateDiagFunc
+	protocols    []string
+}
+
+var (
+	connectionProtocols = []string{"ssh", "telnet", "kubernetes", "rdp", "vnc"}
+	terminalProtocols   = []string{"ssh", "telnet", "kubernetes"}
+	sftpProtocols       = []string{"ssh", "rdp", "vnc"}
+	// sftpServerProtocols connect to a separate SFTP server, SSH connections reuse their own session
+	sftpServerProtocols = []string{"rdp", "vnc"}
+	wakeOnLANProtocols  = []string{"ssh", "telnet", "rdp", "vnc"}
+)
+
+var (
+	validatePort        = validation.ToDiagFunc(validation.IsPortNumber)
+	validatePositive    = validation.ToDiagFunc(validation.IntAtLeast(1))
+	validateNonNegative = validation.ToDiagFunc(validation.IntAtLeast(0))
+	validateLevel       = validation.ToDiagFunc(validation.IntBetween(1, 9))
+)
+
+// parameterSectionDefinitions lists the section blocks in the order of guacamole's connection
+// form, with the prefix dropped from the names of their fields
+var parameterSectionDefinitions = []struct {
+	name        string
+	description string
+	prefix      string
+}{
+	{"network", "Network settings", ""},
+	{"container", "Container settings", ""},
+	{"authentication", "Authentication settings", ""},
+	{"gateway", "Remote desktop gateway settings", "gateway_"},
+	{"basic", "Basic settings", ""},
+	{"display", "Display settings", ""},
+	{"clipboard", "Clipboard settings", ""},
+	{"session", "Session and environment settings", ""},
+	{"terminal", "Terminal behavior settings", ""},
+	{"device_redirection", "Device redirection settings", ""},
+	{"performance", "Performance settings", ""},
+	{"remote_app", "RemoteApp settings", "remote_app_"},
+	{"preconnection", "Preconnection PDU (Hyper-V) settings", "preconnection_"},
+	{"load_balancing", "Load balancing settings", "load_balance_"},
+	{"repeater", "VNC repeater settings", ""},
+	{"audio", "Audio settings", ""},
+	{"typescript", "Typescript (text session recording) settings", "typescript_"},
+	{"recording", "Screen recording settings", "recording_"},
+	{"sftp", "SFTP settings", "sftp_"},
+	{"wake_on_lan", "Wake-on-LAN settings", "wol_"},
+}
+
+// connectionParameters is the registry of the connection parameters managed by the provider.
+// Parameters appear in their section blocks in registry order
+var connectionParameters = []connectionParameter{
+	// network
+	{section: "network", field: "hostname", name: "hostname", valueType: guacdParameterString, description: "Hostname of target", required: true, protocols: connectionProtocols},
+	{section: "network", field: "port", name: "port", valueType: guacdParameterInt, description: "Port for target connection", defaultValue: "22", validate: validatePort, protocols: []string{"ssh"}},
+	{section: "network", field: "port", name: "port", valueType: guacdParameterInt, description: "Port for target connection", defaultValue: "23", validate: validatePort, protocols: []string{"telnet"}},
+	{section: "network", field: "port", name: "port", valueType: guacdParameterInt, description: "Port for target connection", defaultValue: "8080", validate: validatePort, protocols: []string{"kubernetes"}},
+	{section: "network", field: "port", name: "port", valueType: guacdParameterInt, description: "Port for target connection", defaultValue: "3389", validate: validatePort, protocols: []string{"rdp"}},
+	{section: "network", field: "port", name: "port", valueType: guacdParameterInt, description: "Port for target connection", defaultValue: "5900", validate: validatePort, protocols: []string{"vnc"}},
+	{section: "network", field: "public_host_key", name: "host-key", valueType: guacdParameterString, description: "Public host key", protocols: []string{"ssh"}},
+	{section: "network", field: "use_ssl", name: "use-ssl", valueType: guacdParameterBool, description: "Use SSL/TLS", protocols: []string{"kubernetes"}},
+	{section: "network", field: "ignore_cert", name: "ignore-cert", valueType: guacdParameterBool, description: "Ignore certificate errors", protocols: []string{"kubernetes"}},
+	{section: "network", field: "ca_cert", name: "ca-cert", valueType: guacdParameterString, description: "Certificate authority certificate", protocols: []string{"kubernetes"}},
+	{section: "network", field: "reverse_connect", name: "reverse-connect", valueType: guacdParameterBool, description: "Listen for a connection from the VNC server instead of connecting to it", protocols: []string{"vnc"}},
+	{section: "network", field: "listen_timeout", name: "listen-timeout", valueType: guacdParameterInt, description: "Time in milliseconds to wait for the VNC server to connect when reverse_connect is enabled", validate: validateNonNegative, protocols: []string{"vnc"}},
+	{section: "network", field: "autoretry", name: "autoretry", valueType: guacdParameterInt, description: "Number of times to retry connecting", validate: validateNonNegative, protocols: []string{"vnc"}},
+
+	// container
+	{section: "container", field: "namespace", name: "namespace", valueType: guacdParameterString, description: "Namespace name", protocols: []string{"kubernetes"}},
+	{section: "container", field: "pod", name: "pod", valueType: guacdParameterString, description: "Pod name", protocols: []string{"kubernetes"}},
+	{section: "container", field: "container", name: "container", valueType: guacdParameterString, description: "Container name", protocols: []string{"kubernetes"}},
+	{section: "container", field: "exec_command", name: "exec-command", valueType: guacdParameterString, description: "Command to run in the container instead of attaching to it", protocols: []string{"kubernetes"}},
+
+	// authentication
+	{section: "authentication", field: "username", name: "username", valueType: guacdParameterString, description: "Username for target connection", required: true, protocols: []string{"ssh", "telnet", "rdp", "vnc"}},
+	{section: "authentication", field: "password", name: "password", valueType: guacdParameterString, description: "Password for target connection", sensitive: true, protocols: []string{"ssh", "telnet", "rdp", "vnc"}},
+	{section: "authentication", field: "private_key", name: "private-key", valueType: guacdParameterString, description: "Private key for ssh connection", sensitive: true, protocols: []string{"ssh"}},
+	{section: "authentication", field: "passphrase", name: "passphrase", valueType: guacdParameterString, description: "Private key passphrase", sensitive: true, protocols: []string{"ssh"}},
+	{section: "authentication", field: "public_key", name: "public-key", valueType: guacdParameterString, description: "Public key or certificate signed for the private key", protocols: []string{"ssh"}},
+	{section: "authentication", field: "username_regex", name: "username-regex", valueType: guacdParameterString, description: "Username regex for telnet connection", validate: validatePOSIXRegex, protocols: []string{"telnet"}},
+	{section: "authentication", field: "password_regex", name: "password-regex", valueType: guacdParameterString, description: "Password regex for telnet connection", validate: validatePOSIXRegex, protocols: []string{"telnet"}},
+	{section: "authentication", field: "login_success_regex", name: "login-success-regex", valueType: guacdParameterString, description: "Login success regex for telnet connection", validate: validatePOSIXRegex, protocols: []string{"telnet"}},
+	{section: "authentication", field: "login_failure_regex", name: "login-failure-regex", valueType: guacdParameterString, description: "Login failure regex for telnet connection", validate: validatePOSIXRegex, protocols: []string{"telnet"}},
+	{section: "authentication", field: "client_cert", name: "client-cert", valueType: guacdParameterString, description: "Client certificate", protocols: []string{"kubernetes"}},
+	{section: "authentication", field: "client_key", name: "client-key", valueType: guacdParameterString, description: "Client key", sensitive: true, protocols: []string{"kubernetes"}},
+	{section: "authentication", field: "domain", name: "domain", valueType: guacdParameterString, description: "Domain name of rdp connection", protocols: []string{"rdp"}},
+	{section: "authentication", field: "security_mode", name: "security", valueType: guacdParameterString, description: "RDP security mode", options: types.GuacConnectionParameters{}.ValidSecurityModes(), protocols: []string{"rdp"}},
+	{section: "authentication", field: "disable_authentication", name: "disable-auth", valueType: guacdParameterBool, description: "Disable rdp authentication", protocols: []string{"rdp"}},
+	{section: "authentication", field: "ignore_cert", name: "ignore-cert", valueType: guacdParameterBool, description: "Ignore domain certificate warnings", protocols: []string{"rdp"}},
+
+	// gateway
+	{section: "gateway", field: "gateway_hostname", name: "gateway-hostname", valueType: guacdParameterString, description: "RDS gateway hostname", protocols: []string{"rdp"}},
+	{section: "gateway", field: "gateway_port", name: "gateway-port", valueType: guacdParameterInt, description: "RDS gateway port", defaultValue: "443", validate: validatePort, protocols: []string{"rdp"}},
+	{section: "gateway", field: "gateway_username", name: "gateway-username", valueType: guacdParameterString, description: "RDS gateway username", protocols: []string{"rdp"}},
+	{section: "gateway", field: "gateway_password", name: "gateway-password", valueType: guacdParameterString, description: "RDS gateway password", sensitive: true, protocols: []string{"rdp"}},
+	{section: "gateway", field: "gateway_domain", name: "gateway-domain", valueType: guacdParameterString, description: "RDS gateway domain", protocols: []string{"rdp"}},
+
+	// basic
+	{section: "basic", field: "initial_program", name: "initial-program", valueType: guacdParameterString, description: "Initial program for rdp connection", protocols: []string{"rdp"}},
+	{section: "basic", field: "client_name", name: "client-name", valueType: guacdParameterString, description: "Client name for rdp connection", protocols: []string{"rdp"}},
+	{section: "basic", field: "keyboard_layout", name: "server-layout", valueType: guacdParameterString, description: "Keyboard layout for rdp connection", options: validRDPKeyboardLayouts(), protocols: []string{"rdp"}},
+	{section: "basic", field: "timezone", name: "timezone", valueType: guacdParameterString, description: "Timezone/Locale for rdp connection", validate: validateTimezone, protocols: []string{"rdp"}},
+	{section: "basic", field: "administrator_console", name: "console", valueType: guacdParameterBool, description: "Enable administrator console", protocols: []string{"rdp"}},
+
+	// display
+	{section: "display", field: "color_scheme", name: "color-scheme", valueType: guacdParameterColorScheme, description: "Display color scheme", options: types.GuacConnectionParameters{}.ValidColorSchemes(), protocols: terminalProtocols},
+	{section: "display", field: "font_name", name: "font-name", valueType: guacdParameterString, description: "Display font name", protocols: terminalProtocols},
+	{section: "display", field: "font_size", name: "font-size", valueType: guacdParameterInt, description: "Display font size", validate: validatePositive, protocols: terminalProtocols},
+	{section: "display", field: "max_scrollback_size", name: "scrollback", valueType: guacdParameterInt, description: "Display maximum scrollback", validate: validateNonNegative, protocols: terminalProtocols},
+	{section: "display", field: "width", name: "width", valueType: guacdParameterInt, description: "Screen width (px)", validate: validatePositive, protocols: []string{"rdp"}},
+	{section: "display", field: "height", name: "height", valueType: guacdParameterInt, description: "Screen height (px)", validate: validatePositive, protocols: []string{"rdp"}},
+	{section: "display", field: "dpi", name: "dpi", valueType: guacdParameterInt, description: "Resolution (DPI) of rdp connection", validate: validatePositive, protocols: []string{"rdp"}},
+	{section: "display", field: "color_depth", name: "color-depth", valueType: guacdParameterString, description: "Display color depth", options: types.GuacConnectionParameters{}.ValidColorDepths(), protocols: []string{"rdp", "vnc"}},
+	{section: "display", field: "resize_method", name: "resize-method", valueType: guacdParameterString, description: "Resize method rdp connection", options: types.GuacConnectionParameters{}.ValidResizeMethods(), protocols: []string{"rdp"}},
+	{section: "display", field: "swap_red_blue", name: "swap-red-blue", valueType: guacdParameterBool, description: "Swap red/blue Components", protocols: []string{"vnc"}},
+	{section: "display", field: "cursor", name: "cursor", valueType: guacdParameterString, description: "Local or remote cursor", options: types.GuacConnectionParameters{}.ValidCursors(), protocols: []string{"vnc"}},
+	{section: "display", field: "encodings", name: "encodings", valueType: guacdParameterList, description: "VNC encodings to use, in order of preference", protocols: []string{"vnc"}},
+	{section: "display", field: "compress_level", name: "compress-level", valueType: guacdParameterInt, description: "Compression level from 1 (fastest) to 9 (best compression)", validate: validateLevel, protocols: []string{"vnc"}},
+	{section: "display", field: "quality_level", name: "quality-level", valueType: guacdParameterInt, description: "Image quality level from 1 (lowest) to 9 (highest)", validate: validateLevel, protocols: []string{"vnc"}},
+	{section: "display", field: "disable_display_resize", name: "disable-display-resize", valueType: guacdParameterBool, description: "Disable display resize", protocols: []string{"rdp", "vnc"}},
+	{section: "display", field: "force_lossless", name: "force-lossless", valueType: guacdParameterBool, description: "Force lossless compression", protocols: []string{"rdp", "vnc"}},
+	{section: "display", field: "readonly", name: "read-only", valueType: guacdParameterBool, description: "Display is readonly", protocols: connectionProtocols},
+	{section: "display", field: "disable_server_input", name: "disable-server-input", valueType: guacdParameterBool, description: "Disable server input", protocols: []string{"ssh", "telnet", "rdp"}},
+
+	// clipboard
+	{section: "clipboard", field: "disable_copy", name: "disable-copy", valueType: guacdParameterBool, description: "Disable copying from terminal", protocols: connectionProtocols},
+	{section: "clipboard", field: "disable_paste", name: "disable-paste", valueType: guacdParameterBool, description: "Disable pasting from client", protocols: connectionProtocols},
+	{section: "clipboard", field: "clipboard_encoding", name: "clipboard-encoding", valueType: guacdParameterString, description: "Clipboard encoding", options: types.GuacConnectionParameters{}.ValidClipboardEncodings(), protocols: []string{"ssh", "telnet", "kubernetes", "vnc"}},
+	{section: "clipboard", field: "normalize_clipboard", name: "normalize-clipboard", valueType: guacdParameterString, description: "Normalize clipboard line endings", options: validRDPClipboardNormalizations(), protocols: []string{"rdp"}},
+
+	// session
+	{section: "session", field: "execute_command", name: "command", valueType: guacdParameterString, description: "Execute command", protocols: []string{"ssh"}},
+	{section: "session", field: "locale", name: "locale", valueType: guacdParameterString, description: "Language/Locale", protocols: []string{"ssh", "telnet"}},
+	{section: "session", field: "timezone", name: "timezone", valueType: guacdParameterString, description: "Timezone", validate: validateTimezone, protocols: []string{"ssh", "telnet"}},
+	{section: "session", field: "server_keepalive", name: "server-alive-interval", valueType: guacdParameterInt, description: "Server keepalive interval", validate: validateNonNegative, protocols: []string{"ssh"}},
+
+	// terminal
+	{section: "terminal", field: "backspace", name: "backspace", valueType: guacdParameterString, description: "Backspace key sends", options: types.GuacConnectionParameters{}.ValidBackspaceCodes(), protocols: terminalProtocols},
+	{section: "terminal", field: "terminal_type", name: "terminal-type", valueType: guacdParameterString, description: "Terminal type", options: types.GuacConnectionParameters{}.ValidTerminalTypes(), protocols: terminalProtocols},
+
+	// device_redirection
+	{section: "device_redirection", field: "console_audio", name: "console-audio", valueType: guacdParameterBool, description: "Support audio in console", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "disable_audio", name: "disable-audio", valueType: guacdParameterBool, description: "Disable audio", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "enable_audio_input", name: "enable-audio-input", valueType: guacdParameterBool, description: "Enable audio input (microphone)", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "enable_touch", name: "enable-touch", valueType: guacdParameterBool, description: "Enable multi-touch", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "enable_printing", name: "enable-printing", valueType: guacdParameterBool, description: "Enable printing", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "printer_name", name: "printer-name", valueType: guacdParameterString, description: "Redirected printer name", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "enable_drive", name: "enable-drive", valueType: guacdParameterBool, description: "Enable drive for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "drive_name", name: "drive-name", valueType: guacdParameterString, description: "Drive name for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "disable_file_download", name: "disable-download", valueType: guacdParameterBool, description: "Disable file download for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "disable_file_upload", name: "disable-upload", valueType: guacdParameterBool, description: "Disable file upload for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "drive_path", name: "drive-path", valueType: guacdParameterString, description: "Drive path for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "create_drive_path", name: "create-drive-path", valueType: guacdParameterBool, description: "Create drive path for device redirection", protocols: []string{"rdp"}},
+	{section: "device_redirection", field: "static_channels", name: "static-channels", valueType: guacdParameterCommaList, description: "Static channel names", protocols: []string{"rdp"}},
+
+	// performance
+	{section: "performance", field: "enable_wallpaper", name: "enable-wallpaper", valueType: guacdParameterBool, description: "Enable wallpaper", protocols: []string{"rdp"}},
+	{section: "performance", field: "enable_theming", name: "enable-theming", valueType: guacdParameterBool, description: "Enable theming", protocols: []string{"rdp"}},
+	{section: "performance", field: "enable_font_smoothing", name: "enable-font-smoothing", valueType: guacdParameterBool, description: "Enable font smoothing", protocols: []string{"rdp"}},
+	{section: "performance", field: "enable_full_window_drag", name: "enable-full-window-drag", valueType: guacdParameterBool, description: "Enable full window drag", protocols: []string{"rdp"}},
+	{section: "performance", field: "enable_desktop_composition", name: "enable-desktop-composition", valueType: guacdParameterBool, description: "Enable desktop composition", protocols: []string{"rdp"}},
+	{section: "performance", field: "enable_menu_animations", name: "enable-menu-animations", valueType: guacdParameterBool, description: "Enable menu animations", protocols: []string{"rdp"}},
+	{section: "performance", field: "disable_bitmap_caching", name: "disable-bitmap-caching", valueType: guacdParameterBool, description: "Disable bitmap caching", protocols: []string{"rdp"}},
+	{section: "performance", field: "disable_offscreen_caching", name: "disable-offscreen-caching", valueType: guacdParameterBool, description: "Disable off-screen caching", protocols: []string{"rdp"}},
+	{section: "performance", field: "disable_glyph_caching", name: "disable-glyph-caching", valueType: guacdParameterBool, description: "Disable glyph caching", protocols: []string{"rdp"}},
+
+	// remote_app
+	{section: "remote_app", field: "remote_app", sectionField: "program", name: "remote-app", valueType: guacdParameterString, description: "Remote App program", protocols: []string{"rdp"}},
+	{section: "remote_app", field: "remote_app_working_directory", name: "remote-app-dir", valueType: guacdParameterString, description: "Remote App working directory", protocols: []string{"rdp"}},
+	{section: "remote_app", field: "remote_app_parameters", name: "remote-app-args", valueType: guacdParameterString, description: "Remote App parameters", protocols: []string{"rdp"}},
+
+	// preconnection
+	{section: "preconnection", field: "preconnection_id", name: "preconnection-id", valueType: guacdParameterInt, description: "RDP source ID", validate: validateNonNegative, protocols: []string{"rdp"}},
+	{section: "preconnection", field: "preconnection_blob", name: "preconnection-blob", valueType: guacdParameterString, description: "Preconnection BLOB (VM ID)", sensitive: true, protocols: []string{"rdp"}},
+
+	// load_balancing
+	{section: "load_balancing", field: "load_balance_info", name: "load-balance-info", valueType: guacdParameterString, description: "Load balance info/cookie", protocols: []string{"rdp"}},
+
+	// repeater
+	{section: "repeater", field: "destination_host", name: "dest-host", valueType: guacdParameterString, description: "VNC repeater destination host", protocols: []string{"vnc"}},
+	{section: "repeater", field: "destination_port", name: "dest-port", valueType: guacdParameterInt, description: "VNC repeater destination port", validate: validatePort, protocols: []string{"vnc"}},
+
+	// audio
+	{section: "audio", field: "enable_audio", name: "enable-audio", valueType: guacdParameterBool, description: "Enable audio", protocols: []string{"vnc"}},
+	{section: "audio", field: "audio_server_name", name: "audio-servername", valueType: guacdParameterString, description: "Audio server name", protocols: []string{"vnc"}},
+
+	// typescript
+	{section: "typescript", field: "typescript_path", name: "typescript-path", valueType: guacdParameterString, description: "Typescript path", protocols: terminalProtocols},
+	{section: "typescript", field: "typescript_name", name: "typescript-name", valueType: guacdParameterString, description: "Typescript name", protocols: terminalProtocols},
+	{section: "typescript", field: "typescript_auto_create_path", name: "create-typescript-path", valueType: guacdParameterBool, description: "Automatically create typescript path", protocols: terminalProtocols},
+
+	// recording
+	{section: "recording", field: "recording_path", name: "recording-path", valueType: guacdParameterString, description: "Screen recording path", protocols: connectionProtocols},
+	{section: "recording", field: "recording_name", name: "recording-name", valueType: guacdParameterString, description: "Screen recording name", protocols: connectionProtocols},
+	{section: "recording", field: "recording_exclude_output", name: "recording-exclude-output", valueType: guacdParameterBool, description: "Exclude graphics/streams", protocols: connectionProtocols},
+	{section: "recording", field: "recording_exclude_mouse", name: "recording-exclude-mouse", valueType: guacdParameterBool, description: "Exclude mouse", protocols: connectionProtocols},
+	{section: "recording", field: "recording_exclude_touch", name: "recording-exclude-touch", valueType: guacdParameterBool, description: "Exclude touch events", protocols: connectionProtocols},
+	{section: "recording", field: "recording_include_keys", name: "recording-include-keys", valueType: guacdParameterBool, description: "Include key events", protocols: connectionProtocols},
+	{section: "recording", field: "recording_auto_create_path", name: "create-recording-path", valueType: guacdParameterBool, description: "Auto create recording path", protocols: connectionProtocols},
+	{section: "recording", field: "recording_write_existing", name: "recording-write-existing", valueType: guacdParameterBool, description: "Write to existing recording files", protocols: []string{"ssh", "rdp"}},
+
+	// sftp
+	{section: "sftp", field: "sftp_enable", name: "enable-sftp", valueType: guacdParameterBool, description: "Enable sftp", protocols: sftpProtocols},
+	{section: "sftp", field: "sftp_hostname", name: "sftp-hostname", valueType: guacdParameterString, description: "SFTP server hostname", protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_port", name: "sftp-port", valueType: guacdParameterInt, description: "SFTP server port", validate: validatePort, protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_host_key", name: "sftp-host-key", valueType: guacdParameterString, description: "SFTP server public host key (Base64)", protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_username", name: "sftp-username", valueType: guacdParameterString, description: "SFTP server username", protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_password", name: "sftp-password", valueType: guacdParameterString, description: "SFTP server password", sensitive: true, protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_private_key", name: "sftp-private-key", valueType: guacdParameterString, description: "SFTP server private key", sensitive: true, protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_passphrase", name: "sftp-passphrase", valueType: guacdParameterString, description: "SFTP server private key passphrase", sensitive: true, protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_root_directory", name: "sftp-root-directory", valueType: guacdParameterString, description: "File browser root directory", protocols: sftpProtocols},
+	{section: "sftp", field: "sftp_upload_directory", name: "sftp-directory", valueType: guacdParameterString, description: "SFTP default upload directory", protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_keepalive_interval", name: "sftp-server-alive-interval", valueType: guacdParameterInt, description: "SFTP keepalive interval", validate: validateNonNegative, protocols: sftpServerProtocols},
+	{section: "sftp", field: "sftp_disable_file_download", name: "sftp-disable-download", valueType: guacdParameterBool, description: "Disable file download", protocols: sftpProtocols},
+	{section: "sftp", field: "sftp_disable_file_upload", name: "sftp-disable-upload", valueType: guacdParameterBool, description: "Disable file upload", protocols: sftpProtocols},
+
+	// wake_on_lan
+	{section: "wake_on_lan", field: "wol_send_packet", name: "wol-send-packet", valueType: guacdParameterBool, description: "Send WoL packet", protocols: wakeOnLANProtocols},
+	{section: "wake_on_lan", field: "wol_mac_address", name: "wol-mac-addr", valueType: guacdParameterString, description: "MAC address of the remote host", protocols: wakeOnLANProtocols},
+	{section: "wake_on_lan", field: "wol_broadcast_address", name: "wol-broadcast-addr", valueType: guacdParameterString, description: "Broadcast address for WoL packet", protocols: wakeOnLANProtocols},
+	{section: "wake_on_lan", field: "wol_boot_wait_time", name: "wol-wait-time", valueType: guacdParameterInt, description: "Host boot wait time", validate: validateNonNegative, protocols: wakeOnLANProtocols},
+}
+
+// guacConnectionParameterFields maps the json names of the api client connection parameters to
+// the index of their struct field.  Registry parameters missing from it are guacd parameters
+// the api client doesn't know about, sent and read through the raw parameter map
+var guacConnectionParameterFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(types.GuacConnectionParameters{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// protocolConnectionParameters returns the registry parameters of a protocol
+func protocolConnectionParameters(protocol string) []connectionParameter {
+	var parameters []connectionParameter
+	for _, p := range connectionParameters {
+		if stringSliceContains(p.protocols, protocol) {
+			parameters = append(parameters, p)
+		}
+	}
+	return parameters
+}
+
+// connectionParameterSections returns the section blocks of a protocol
+func connectionParameterSections(protocol string) []parameterSection {
+	var sections []parameterSection
+	for _, definition := range parameterSectionDefinitions {
+		section := parameterSection{name: definition.name, description: definition.description}
+		for _, p := range protocolConnectionParameters(protocol) {
+			if p.section != definition.name {
+				continue
+			}
+			nested := p.sectionField
+			if nested == "" {
+				nested = strings.TrimPrefix(p.field, definition.prefix)
+			}
+			section.fields = append(section.fields, parameterSectionField{p.field, nested})
+			if p.valueType == guacdParameterColorScheme {
+				section.fields = append(section.fields, parameterSectionField{"custom_color_scheme", "custom_color_scheme"})
+			}
+		}
+		if len(section.fields) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// schemaType returns the terraform type of a parameter
+func (p connectionParameter) schemaType() schema.ValueType {
+	switch p.valueType {
+	case guacdParameterBool:
+		return schema.TypeBool
+	case guacdParameterInt:
+		return schema.TypeInt
+	case guacdParameterList, guacdParameterCommaList:
+		return schema.TypeList
+	default:
+		return schema.TypeString
+	}
+}
+
+// resourceSchema returns the schema of a parameter in the parameters block of a resource
+func (p connectionParameter) resourceSchema() *schema.Schema {
+	s := &schema.Schema{
+		Type:             p.schemaType(),
+		Description:      p.description,
+		Optional:         !p.required,
+		Required:         p.required,
+		Sensitive:        p.sensitive,
+		ValidateDiagFunc: p.validate,
+	}
+	if s.Type == schema.TypeList {
+		s.Elem = &schema.Schema{Type: schema.TypeString}
+	}
+	if p.options != nil && s.ValidateDiagFunc == nil {
+		s.ValidateDiagFunc = validateStringOption(p.options)
+	}
+	if p.sensitive {
+		s.DiffSuppressFunc = suppressSecretHashDiff
+	} else if p.defaultValue != "" {
+		s.DiffSuppressFunc = suppressDefaultValueDiff(p.defaultValue)
+	}
+	return s
+}
+
+// dataSourceSchema returns the schema of a parameter in the parameters block of a data source
+func (p connectionParameter) dataSourceSchema() *schema.Schema {
+	s := &schema.Schema{
+		Type:        p.schemaType(),
+		Description: p.description,
+		Computed:    true,
+		Sensitive:   p.sensitive,
+	}
+	if s.Type == schema.TypeList {
+		s.Elem = &schema.Schema{Type: schema.TypeString}
+	}
+	return s
+}
+
+// connectionParametersSchema returns the fields of the parameters block of a protocol, computed
+// for data sources
+func connectionParametersSchema(protocol string, computed bool) map[string]*schema.Schema {
+	fields := make(map[string]*schema.Schema)
+	for _, p := range protocolConnectionParameters(protocol) {
+		if computed {
+			fields[p.field] = p.dataSourceSchema()
+		} else {
+			fields[p.field] = p.resourceSchema()
+		}
+		if p.valueType == guacdParameterColorScheme {
+			if computed {
+				fields["custom_color_scheme"] = dataSourceCustomColorSchemeSchema()
+			} else {
+				fields["custom_color_scheme"] = customColorSchemeSchema()
+			}
+		}
+	}
+	return fields
+}
+
+// expand converts the terraform value of a parameter into guacamole's string encoding, leaving
+// unset values empty.  Integers are only sent as 0 when configured.  Color schemes take the whole
+// parameters block as they span two fields
+func (p connectionParameter) expand(values map[string]interface{}, configured bool) string {
+	switch p.valueType {
+	case guacdParameterBool:
+		return boolToString(values[p.field].(bool))
+	case guacdParameterInt:
+		return configuredIntToString(values[p.field].(int), configured)
+	case guacdParameterList:
+		var elements []string
+		for _, element := range values[p.field].([]interface{}) {
+			elements = append(elements, element.(string))
+		}
+		return strings.Join(elements, " ")
+	case guacdParameterCommaList:
+		return listToString(values[p.field].([]interface{}))
+	case guacdParameterColorScheme:
+		return expandColorScheme(values)
+	default:
+		return values[p.field].(string)
+	}
+}
+
+// flatten converts the guacamole string encoding of a parameter into its terraform values
+func (p connectionParameter) flatten(value string, values map[string]interface{}) {
+	switch p.valueType {
+	case guacdParameterBool:
+		values[p.field] = stringToBool(value)
+	case guacdParameterInt:
+		values[p.field] = stringToInt(value)
+	case guacdParameterList:
+		values[p.field] = strings.Fields(value)
+	case guacdParameterCommaList:
+		values[p.field] = stringToList(value)
+	case guacdParameterColorScheme:
+		values[p.field], values["custom_color_scheme"] = flattenColorScheme(value)
+	default:
+		values[p.field] = value
+	}
+}
+
+// expandConnectionParameters converts a flat parameters block into the parameters of a
+// guacamole connection.  The guacd parameters missing from the api client types are returned
+// separately, without unset values.  The raw configuration tells configured zero integers apart
+// from unset ones
+func expandConnectionParameters(protocol string, values map[string]interface{}, config cty.Value) (types.GuacConnectionParameters, map[string]string) {
+	var parameters types.GuacConnectionParameters
+	guacd := make(map[string]string)
+
+	sections := connectionParameterSections(protocol)
+	fields := reflect.ValueOf(&parameters).Elem()
+	for _, p := range protocolConnectionParameters(protocol) {
+		_, configured := configuredParameter(config, sections, p.field)
+		value := p.expand(values, configured)
+		if i, ok := guacConnectionParameterFields[p.name]; ok {
+			fields.Field(i).SetString(value)
+		} else if value != "" {
+			guacd[p.name] = value
+		}
+	}
+
+	return parameters, guacd
+}
+
+// flattenConnectionParameters converts the parameters of a guacamole connection and the raw
+// guacd parameters read alongside it into a flat parameters block
+func flattenConnectionParameters(protocol string, parameters *types.GuacConnectionParameters, guacd map[string]string) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	fields := reflect.ValueOf(parameters).Elem()
+	for _, p := range protocolConnectionParameters(protocol) {
+		if i, ok := guacConnectionParameterFields[p.name]; ok {
+			p.flatten(fields.Field(i).String(), values)
+		} else {
+			p.flatten(guacd[p.name], values)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,102 @@
+package guacamole
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// testRegistryValue returns a set value of a registry parameter
+func testRegistryValue(p connectionParameter) interface{} {
+	switch p.valueType {
+	case guacdParameterBool:
+		return true
+	case guacdParameterInt:
+		return 7
+	case guacdParameterList, guacdParameterCommaList:
+		return []interface{}{"first", "second"}
+	}
+	if len(p.options) > 0 {
+		return p.options[len(p.options)-1]
+	}
+	return fmt.Sprintf("%s value", p.field)
+}
+
+func TestConnectionParametersRoundTrip(t *testing.T) {
+	for _, protocol := range connectionProtocols {
+		values := make(map[string]interface{})
+		for _, p := range protocolConnectionParameters(protocol) {
+			values[p.field] = testRegistryValue(p)
+		}
+
+		parameters, guacd := expandConnectionParameters(protocol, values, cty.NilVal)
+
+		// the api client parameters go through their json encoding like in a guacamole request
+		body, err := json.Marshal(parameters)
+		if err != nil {
+			t.Fatalf("%s: unable to encode parameters: %s", protocol, err)
+		}
+		var decoded types.GuacConnectionParameters
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Fatalf("%s: unable to decode parameters: %s", protocol, err)
+		}
+		encoded := make(map[string]string)
+		if err := json.Unmarshal(body, &encoded); err != nil {
+			t.Fatalf("%s: unable to decode parameters: %s", protocol, err)
+		}
+
+		for _, p := range protocolConnectionParameters(protocol) {
+			_, inStruct := guacConnectionParameterFields[p.name]
+			_, inGuacd := guacd[p.name]
+			if inStruct && (encoded[p.name] == "" || inGuacd) {
+				t.Errorf("%s: parameter %s (%s) is not sent through types.GuacConnectionParameters", protocol, p.field, p.name)
+			}
+			if !inStruct && !inGuacd {
+				t.Errorf("%s: parameter %s (%s) is not sent as a guacd parameter", protocol, p.field, p.name)
+			}
+		}
+
+		flattened := flattenConnectionParameters(protocol, &decoded, guacd)
+		for k, v := range values {
+			expected := v
+			if list, ok := v.([]interface{}); ok {
+				var elements []string
+				for _, element := range list {
+					elements = append(elements, element.(string))
+				}
+				expected = elements
+			}
+			if !reflect.DeepEqual(flattened[k], expected) {
+				t.Errorf("%s: parameter %s: expected %#v, got %#v", protocol, k, expected, flattened[k])
+			}
+		}
+	}
+}
+
+func TestConnectionParametersRegistry(t *testing.T) {
+	sections := make(map[string]bool)
+	for _, definition := range parameterSectionDefinitions {
+		sections[definition.name] = true
+	}
+
+	for _, protocol := range connectionProtocols {
+		fields := make(map[string]bool)
+		for _, p := range protocolConnectionParameters(protocol) {
+			if fields[p.field] {
+				t.Errorf("%s: parameter %s is registered more than once", protocol, p.field)
+			}
+			fields[p.field] = true
+			if !sections[p.section] {
+				t.Errorf("%s: parameter %s has unknown section %s", protocol, p.field, p.section)
+			}
+			// secrets are hashed and redacted through the api client parameters
+			if _, ok := guacConnectionParameterFields[p.name]; p.sensitive && !ok {
+				t.Errorf("%s: sensitive parameter %s is not an api client parameter", protocol, p.field)
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package guacamole
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// parameterSectionField maps a flat field of the parameters block to its name in a section block
+type parameterSectionField struct {
+	flat   string
+	nested string
+}
+
+// parameterSection is a block of connection parameters mirroring a section of guacamole's
+// connection form
+type parameterSection struct {
+	name        string
+	description string
+	fields      []parameterSectionField
+}
+
+// parameterSectionPath returns the section block and field name of a flat parameter
+func parameterSectionPath(sections []parameterSection, flat string) (string, string, bool) {
+	for _, section := range sections {
+		for _, f := range section.fields {
+			if f.flat == flat {
+				return section.name, f.nested, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// parameterGetter reads resource values, either from a ResourceData or a ResourceDiff
+type parameterGetter interface {
+	Get(key string) interface{}
+}
+
+// addParameterSections adds the section blocks of a connection schema and deprecates the flat
+// parameters block they replace.  Sections holding required parameters are required unless the
+// parameters block is used.  Sections of computed fields only, like those of data sources, are
+// computed
+func addParameterSections(s map[string]*schema.Schema, sections []parameterSection) {
+	elem := s["parameters"].Elem.(*schema.Resource)
+
+	for _, section := range sections {
+		fields := make(map[string]*schema.Schema, len(section.fields))
+		computed := true
+		required := false
+		for _, f := range section.fields {
+			flat := elem.Schema[f.flat]
+			nested := *flat
+			nested.ConflictsWith = nil
+			for _, c := range flat.ConflictsWith {
+				if n, field, ok := parameterSectionPath(sections, strings.TrimPrefix(c, "parameters.0.")); ok {
+					c = fmt.Sprintf("%s.0.%s", n, field)
+				}
+				nested.ConflictsWith = append(nested.ConflictsWith, c)
+			}
+			fields[f.nested] = &nested
+			computed = computed && flat.Computed && !flat.Optional
+			required = required || flat.Required
+		}
+
+		block := &schema.Schema{
+			Type:        schema.TypeList,
+			Description: section.description,
+			Elem:        &schema.Resource{Schema: fields},
+		}
+		if computed {
+			block.Computed = true
+		} else {
+			block.Optional = true
+			block.MaxItems = 1
+			if required {
+				block.ExactlyOneOf = []string{"parameters", section.name}
+			} else {
+				block.ConflictsWith = []string{"parameters"}
+			}
+			block.DiffSuppressFunc = suppressDefaultSectionDiff(section.name, fields)
+		}
+		s[section.name] = block
+	}
+
+	var names []string
+	for _, section := range sections {
+		names = append(names, section.name)
+	}
+	s["parameters"].Deprecated = fmt.Sprintf("Use the %s blocks instead, the parameters block will be removed in the next major version", strings.Join(names, ", "))
+}
+
+// suppressDefaultSectionDiff suppresses the removal of a section block that isn't configured
+// when every value read back into it is unset or suppressed as a default value
+func suppressDefaultSectionDiff(name string, fields map[string]*schema.Schema) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if k != name+".#" || old != "1" || new != "0" {
+			return false
+		}
+
+		previous, _ := d.GetChange(name)
+		list, _ := previous.([]interface{})
+		if len(list) == 0 || list[0] == nil {
+			return true
+		}
+		for field, value := range list[0].(map[string]interface{}) {
+			if isZeroValue(value) {
+				continue
+			}
+			s, ok := fields[field]
+			if !ok || s.DiffSuppressFunc == nil {
+				return false
+			}
+			var encoded string
+			switch v := value.(type) {
+			case string:
+				encoded = v
+			case int:
+				encoded = strconv.Itoa(v)
+			case bool:
+				encoded = strconv.FormatBool(v)
+			default:
+				return false
+			}
+			if !s.DiffSuppressFunc(fmt.Sprintf("%s.0.%s", name, field), encoded, "", d) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// mergeParameterSections returns the values of the section blocks and of the deprecated
+// parameters block as a single flat parameters block, so that both layouts can be handled the
+// same way.  Values set in a section take precedence
+func mergeParameterSections(d parameterGetter, sections []parameterSection) []interface{} {
+	merged := make(map[string]interface{})
+	for _, section := range sections {
+		for _, f := range section.fields {
+			merged[f.flat] = d.Get(fmt.Sprintf("parameters.0.%s", f.flat))
+			if v := d.Get(fmt.Sprintf("%s.0.%s", section.name, f.nested)); !isZeroValue(v) {
+				merged[f.flat] = v
+			}
+		}
+	}
+
+	return []interface{}{merged}
+}
+
+// parameterSectionsInUse returns whether a resource uses the section blocks, which is the case
+// unless the deprecated parameters block holds values and no section block is set
+func parameterSectionsInUse(d parameterGetter, sections []parameterSection) bool {
+	for _, section := range sections {
+		if block, _ := d.Get(section.name).([]interface{}); len(block) > 0 {
+			return true
+		}
+	}
+
+	parameterList, _ := d.Get("parameters").([]interface{})
+	if len(parameterList) == 0 || parameterList[0] == nil {
+		return true
+	}
+	for _, v := range parameterList[0].(map[string]interface{}) {
+		if !isZeroValue(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// setConnectionParameters sets flat parameters read from guacamole the way the connection is
+// configured.  Resources using the section blocks, and imported ones, get their values in the
+// section blocks while resources still using the deprecated parameters block keep it.  Data
+// sources get both.  Sections without values are left out unless already in state
+func setConnectionParameters(d *schema.ResourceData, parameters map[string]interface{}, sections []parameterSection) error {
+	// data sources share the conversion functions but have no secret_state_mode argument
+	_, isResource := d.Get("secret_state_mode").(string)
+
+	inUse := parameterSectionsInUse(d, sections)
+	if !isResource || !inUse {
+		if err := d.Set("parameters", []interface{}{parameters}); err != nil {
+			return err
+		}
+	} else if err := d.Set("parameters", nil); err != nil {
+		return err
+	}
+	if isResource && !inUse {
+		for _, section := range sections {
+			if err := d.Set(section.name, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, section := range sections {
+		values := make(map[string]interface{}, len(section.fields))
+		empty := true
+		for _, f := range section.fields {
+			values[f.nested] = parameters[f.flat]
+			if !isZeroValue(parameters[f.flat]) {
+				empty = false
+			}
+		}
+
+		var block []interface{}
+		if previous, _ := d.Get(section.name).([]interface{}); !empty || len(previous) > 0 || !isResource {
+			block = []interface{}{values}
+		}
+		if err := d.Set(section.name, block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// configuredParameter returns the raw configuration of a flat parameter, looking it up in its
+// section block before the deprecated parameters block, if it is configured
+func configuredParameter(config cty.Value, sections []parameterSection, flat string) (cty.Value, bool) {
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() {
+		return cty.NilVal, false
+	}
+	if value, ok := configuredSectionParameter(config, sections, flat); ok && !value.IsNull() && value.IsKnown() {
+		return value, true
+	}
+	return configuredBlockValue(config, "parameters", flat)
+}
+
+// configuredSectionParameter returns the raw configuration of the section field replacing a
+// flat parameter, if its section block is configured
+func configuredSectionParameter(config cty.Value, sections []parameterSection, flat string) (cty.Value, bool) {
+	name, nested, ok := parameterSectionPath(sections, flat)
+	if !ok || !config.Type().HasAttribute(name) {
+		return cty.NilVal, false
+	}
+	block := config.GetAttr(name)
+	if block.IsNull() || !block.IsKnown() || block.LengthInt() == 0 {
+		return cty.NilVal, false
+	}
+	section := block.AsValueSlice()[0]
+	if section.IsNull() || !section.Type().HasAttribute(nested) {
+		return cty.NilVal, false
+	}
+	return section.GetAttr(nested), true
+}
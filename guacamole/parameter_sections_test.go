@@ -0,0 +1,254 @@
+package guacamole
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParameterSectionsCoverage(t *testing.T) {
+	resources := map[string]struct {
+		resource   *schema.Resource
+		dataSource *schema.Resource
+		sections   []parameterSection
+	}{
+		"ssh":        {guacamoleConnectionSSH(), dataSourceConnectionSSH(), connectionParameterSections("ssh")},
+		"telnet":     {guacamoleConnectionTelnet(), dataSourceConnectionTelnet(), connectionParameterSections("telnet")},
+		"kubernetes": {guacamoleConnectionKubernetes(), dataSourceConnectionKubernetes(), connectionParameterSections("kubernetes")},
+		"rdp":        {guacamoleConnectionRDP(), dataSourceConnectionRDP(), connectionParameterSections("rdp")},
+		"vnc":        {guacamoleConnectionVNC(), dataSourceConnectionVNC(), connectionParameterSections("vnc")},
+	}
+
+	for name, r := range resources {
+		t.Run(name, func(t *testing.T) {
+			for _, s := range []map[string]*schema.Schema{r.resource.Schema, r.dataSource.Schema} {
+				mapped := make(map[string]bool)
+				for _, section := range r.sections {
+					block, ok := s[section.name]
+					if !ok {
+						t.Fatalf("section %s is missing from the schema", section.name)
+					}
+					fields := block.Elem.(*schema.Resource).Schema
+					if len(fields) != len(section.fields) {
+						t.Fatalf("section %s has duplicate fields", section.name)
+					}
+					for _, f := range section.fields {
+						if mapped[f.flat] {
+							t.Fatalf("parameter %s is mapped to more than one section", f.flat)
+						}
+						mapped[f.flat] = true
+					}
+				}
+
+				for k := range s["parameters"].Elem.(*schema.Resource).Schema {
+					if !mapped[k] {
+						t.Fatalf("parameter %s isn't mapped to a section", k)
+					}
+				}
+				if s["parameters"].Deprecated == "" {
+					t.Fatalf("expected the parameters block to be deprecated")
+				}
+			}
+		})
+	}
+}
+
+func TestAddParameterSectionsComputed(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"parameters": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"hostname": {Type: schema.TypeString, Optional: true},
+					"port":     {Type: schema.TypeInt, Computed: true},
+					"username": {Type: schema.TypeString, Computed: true},
+				},
+			},
+		},
+	}
+	sections := []parameterSection{
+		{name: "network", fields: []parameterSectionField{{"hostname", "hostname"}, {"port", "port"}}},
+		{name: "authentication", fields: []parameterSectionField{{"username", "username"}}},
+	}
+	addParameterSections(s, sections)
+
+	if s["network"].Computed || !s["network"].Optional {
+		t.Fatalf("expected a section with configurable fields to be optional")
+	}
+	if !s["authentication"].Computed || s["authentication"].Optional {
+		t.Fatalf("expected a section of computed fields only to be computed")
+	}
+}
+
+func TestSetConnectionParameters(t *testing.T) {
+	r := guacamoleConnectionSSH()
+	parameters := func() map[string]interface{} {
+		values := mergeParameterSections(r.Data(nil), connectionParameterSections("ssh"))[0].(map[string]interface{})
+		values["hostname"] = "testing.example.com"
+		values["port"] = 22
+		values["username"] = "user"
+		values["sftp_enable"] = true
+		return values
+	}
+
+	// imported resources use the section blocks
+	d := r.Data(nil)
+	if err := setConnectionParameters(d, parameters(), connectionParameterSections("ssh")); err != nil {
+		t.Fatalf("unexpected error setting parameters: %s", err)
+	}
+	if d.Get("network.0.hostname") != "testing.example.com" || d.Get("network.0.port") != 22 || d.Get("sftp.0.enable") != true {
+		t.Fatalf("expected values in the section blocks, got %#v and %#v", d.Get("network"), d.Get("sftp"))
+	}
+	if len(d.Get("display").([]interface{})) != 0 {
+		t.Fatalf("expected section without values to be left out, got %#v", d.Get("display"))
+	}
+	if len(d.Get("parameters").([]interface{})) != 0 {
+		t.Fatalf("expected the parameters block to be left unset")
+	}
+
+	// resources configured with the deprecated parameters block keep it
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":       "ssh",
+		"parameters": []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+	})
+	if err := setConnectionParameters(d, parameters(), connectionParameterSections("ssh")); err != nil {
+		t.Fatalf("unexpected error setting parameters: %s", err)
+	}
+	if d.Get("parameters.0.username") != "user" || len(d.Get("network").([]interface{})) != 0 {
+		t.Fatalf("expected the parameters block to be kept, got %#v", d.Get("parameters"))
+	}
+
+	// sections already in state are kept even without values
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":      "ssh",
+		"network":   []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+		"clipboard": []interface{}{map[string]interface{}{"disable_copy": false}},
+	})
+	if err := setConnectionParameters(d, parameters(), connectionParameterSections("ssh")); err != nil {
+		t.Fatalf("unexpected error setting parameters: %s", err)
+	}
+	if len(d.Get("clipboard").([]interface{})) != 1 {
+		t.Fatalf("expected configured clipboard section to be kept, got %#v", d.Get("clipboard"))
+	}
+
+	// data sources get both layouts
+	d = dataSourceConnectionSSH().Data(nil)
+	if err := setConnectionParameters(d, parameters(), connectionParameterSections("ssh")); err != nil {
+		t.Fatalf("unexpected error setting parameters: %s", err)
+	}
+	if d.Get("parameters.0.hostname") != "testing.example.com" || d.Get("network.0.hostname") != "testing.example.com" {
+		t.Fatalf("expected data source parameters to have both layouts")
+	}
+}
+
+func TestMergeParameterSections(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionRDP().Schema, map[string]interface{}{
+		"name":       "rdp",
+		"network":    []interface{}{map[string]interface{}{"hostname": "testing.example.com", "port": 3390}},
+		"remote_app": []interface{}{map[string]interface{}{"program": "||notepad"}},
+		"device_redirection": []interface{}{map[string]interface{}{
+			"static_channels": []interface{}{"channel-1"},
+		}},
+	})
+
+	merged := mergeParameterSections(d, connectionParameterSections("rdp"))[0].(map[string]interface{})
+	if merged["hostname"] != "testing.example.com" || merged["port"] != 3390 || merged["remote_app"] != "||notepad" {
+		t.Fatalf("expected section values to be merged, got %#v", merged)
+	}
+	if !reflect.DeepEqual(merged["static_channels"], []interface{}{"channel-1"}) {
+		t.Fatalf("expected static_channels to be merged, got %#v", merged["static_channels"])
+	}
+	if merged["username"] != "" || merged["width"] != 0 {
+		t.Fatalf("expected unset parameters to be zero values, got %#v and %#v", merged["username"], merged["width"])
+	}
+
+	connection, _, diags := convertResourceDataToGuacConnection(d, "rdp")
+	if diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if connection.Parameters.Hostname != "testing.example.com" || connection.Parameters.RemoteApp != "||notepad" || connection.Parameters.StaticChannels != "channel-1" {
+		t.Fatalf("expected section values to be sent, got %#v", connection.Parameters)
+	}
+}
+
+func TestParameterSectionsConflictWithParameters(t *testing.T) {
+	r := guacamoleConnectionSSH()
+	diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":       "ssh",
+		"parameters": []interface{}{map[string]interface{}{"username": "user"}},
+		"network":    []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+	}))
+	if !diags.HasError() {
+		t.Fatalf("expected the parameters block combined with sections to be rejected")
+	}
+
+	diags = r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":           "ssh",
+		"network":        []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+		"authentication": []interface{}{map[string]interface{}{"username": "user"}},
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error for section blocks: %v", diags)
+	}
+
+	diags = r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":    "ssh",
+		"network": []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+	}))
+	if !diags.HasError() {
+		t.Fatalf("expected missing authentication section to be rejected")
+	}
+
+	diags = r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "ssh",
+		"parameters": []interface{}{map[string]interface{}{
+			"hostname": "testing.example.com",
+			"username": "user",
+		}},
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error for the parameters block: %v", diags)
+	}
+}
+
+func TestSuppressDefaultSectionDiff(t *testing.T) {
+	r := guacamoleConnectionRDP()
+
+	// a gateway section only holding the default port isn't removed when not configured
+	state := r.Data(nil)
+	state.SetId("1")
+	state.Set("name", "rdp")
+	state.Set("parent_identifier", "ROOT")
+	state.Set("secret_state_mode", secretStateModePlaintext)
+	state.Set("network", []interface{}{map[string]interface{}{"hostname": "testing.example.com"}})
+	state.Set("gateway", []interface{}{map[string]interface{}{"port": 443}})
+
+	config := map[string]interface{}{
+		"name":    "rdp",
+		"network": []interface{}{map[string]interface{}{"hostname": "testing.example.com"}},
+	}
+	diff, err := r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("unexpected error computing diff: %s", err)
+	}
+	if diff != nil {
+		for k, v := range diff.Attributes {
+			if v.Old != v.New {
+				t.Fatalf("unexpected diff on %s: %q => %q", k, v.Old, v.New)
+			}
+		}
+	}
+
+	state.Set("gateway", []interface{}{map[string]interface{}{"hostname": "gateway.example.com", "port": 443}})
+	diff, err = r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("unexpected error computing diff: %s", err)
+	}
+	if diff == nil || diff.Attributes["gateway.#"] == nil {
+		t.Fatalf("expected the removal of a gateway to be planned")
+	}
+}
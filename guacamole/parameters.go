@@ -0,0 +1,36 @@
+package guacamole
+
+import (
+	"fmt"
+	"net/http"
+
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+// ReadConnectionParameters gets the raw parameter map of a connection
+func (c *guacamoleClient) ReadConnectionParameters(identifier string) (map[string]string, error) {
+	var ret map[string]string
+	err := c.call(http.MethodGet, c.dataSourceURL(fmt.Sprintf("%s/parameters", connectionObjectPath(identifier))), nil, &ret)
+	return ret, err
+}
+
+// WriteConnection sends a connection to guacamole with its attribute map replaced by attributes
+// and the guacd parameters missing from the api client types added to its parameters, decoding
+// the response into result if it is not nil
+func (c *guacamoleClient) WriteConnection(method string, path string, connection *types.GuacConnection, attributes map[string]interface{}, parameters map[string]string, result interface{}) error {
+	body, err := objectBody(connection, attributes)
+	if err != nil {
+		return err
+	}
+
+	merged, _ := body["parameters"].(map[string]interface{})
+	if merged == nil {
+		merged = make(map[string]interface{})
+	}
+	for k, v := range parameters {
+		merged[k] = v
+	}
+	body["parameters"] = merged
+
+	return c.call(method, c.dataSourceURL(path), body, result)
+}
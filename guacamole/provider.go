@@ -66,6 +66,21 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("GUACAMOLE_DISABLE_COOKIES", false),
 			},
+			"omit_data_source_secrets": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GUACAMOLE_OMIT_DATA_SOURCE_SECRETS", false),
+			},
+			"disable_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GUACAMOLE_DISABLE_CACHE", false),
+			},
+			"session_cache_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GUACAMOLE_SESSION_CACHE_PATH", nil),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"guacamole_user":                  guacamoleUser(),
@@ -76,6 +91,7 @@ func Provider() *schema.Provider {
 			"guacamole_connection_vnc":        guacamoleConnectionVNC(),
 			"guacamole_connection_kubernetes": guacamoleConnectionKubernetes(),
 			"guacamole_connection_group":      guacamoleConnectionGroup(),
+			"guacamole_self_password":         guacamoleSelfPassword(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"guacamole_user":                  dataSourceUser(),
@@ -86,6 +102,10 @@ func Provider() *schema.Provider {
 			"guacamole_connection_vnc":        dataSourceConnectionVNC(),
 			"guacamole_connection_kubernetes": dataSourceConnectionKubernetes(),
 			"guacamole_connection_group":      dataSourceConnectionGroup(),
+			"guacamole_connection_access":     dataSourceConnectionAccess(),
+			"guacamole_self":                  dataSourceSelf(),
+			"guacamole_protocols":             dataSourceProtocols(),
+			"guacamole_server":                dataSourceServer(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -99,6 +119,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	data_source := d.Get("data_source").(string)
 	disableTLS := d.Get("disable_tls_verification").(bool)
 	disableCookies := d.Get("disable_cookies").(bool)
+	omitSecrets := d.Get("omit_data_source_secrets").(bool)
+	disableCache := d.Get("disable_cache").(bool)
+	sessionCachePath := d.Get("session_cache_path").(string)
 
 	cookies := make(map[string]string)
 	cookieMap := d.Get("cookies").(map[string]interface{})
@@ -129,9 +152,16 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, check
 	}
 
-	client := guac.New(config)
+	if token != "" && sessionCachePath != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Ignoring session_cache_path",
+			Detail:   "Argument session_cache_path only applies to username and password authentication and is ignored when a token is provided",
+		})
+		sessionCachePath = ""
+	}
 
-	err := client.Connect()
+	client, err := connectGuacamole(config, sessionCachePath)
 
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
@@ -142,8 +172,10 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 		return nil, diags
 	}
+	client.omitDataSourceSecrets = omitSecrets
+	client.cache.disabled = disableCache
 
-	return &client, diags
+	return client, diags
 }
 
 // validate validates the config needed to initialize a guacamole client,
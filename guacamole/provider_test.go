@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -27,7 +28,22 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+// testAccTest runs an acceptance test with terraform when TF_ACC is set.  Otherwise its steps run
+// in process against a fake guacamole, so the acceptance tests run without terraform or network
+// access
+func testAccTest(t *testing.T, c resource.TestCase) {
+	t.Helper()
+
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		testFakeTestCase(t, c)
+		return
+	}
+	resource.Test(t, c)
+}
+
 func testAccPreCheck(t *testing.T) {
+	useFakeGuacamole(t)
+
 	if err := os.Getenv("GUACAMOLE_URL"); err == "" {
 		t.Fatal("GUACAMOLE_URL must be set for acceptance tests")
 	}
@@ -2,22 +2,23 @@ package guacamole
 
 import (
 	"context"
-	"fmt"
-	"strconv"
+	"net/http"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
 func guacamoleConnectionGroup() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
+		SchemaVersion: 1,
 		CreateContext: resourceConnectionGroupCreate,
 		ReadContext:   resourceConnectionGroupRead,
 		UpdateContext: resourceConnectionGroupUpdate,
 		DeleteContext: resourceConnectionGroupDelete,
+		CustomizeDiff: extraAttributesCustomizeDiff(attributeSchemaConnectionGroup, typedConnectionGroupAttributes),
 		Schema: map[string]*schema.Schema{
 			"identifier": {
 				Type:        schema.TypeString,
@@ -48,43 +49,41 @@ func guacamoleConnectionGroup() *schema.Resource {
 				Description: "Active connections of guacamole connection group",
 				Computed:    true,
 			},
-			"attributes": {
-				Type:        schema.TypeList,
-				Description: "Attributes of guacamole connection group",
-				Optional:    true,
-				Computed:    true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"max_connections": {
-							Type:        schema.TypeString,
-							Description: "Maximum number of total simultaneous connections allowed",
-							Optional:    true,
-							Computed:    true,
-						},
-						"max_connections_per_user": {
-							Type:        schema.TypeString,
-							Description: "Maximum number of simultaneous connections allowed per user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"enable_session_affinity": {
-							Type:        schema.TypeBool,
-							Description: "Enable session affinity",
-							Optional:    true,
-							Computed:    true,
-						},
-					},
+			"extra_attributes": extraAttributesSchema(),
+			"attributes": attributesSchema("Attributes of guacamole connection group", map[string]*schema.Schema{
+				"max_connections": {
+					Type:             schema.TypeInt,
+					Description:      "Maximum number of total simultaneous connections allowed",
+					Optional:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
 				},
-			},
+				"max_connections_per_user": {
+					Type:             schema.TypeInt,
+					Description:      "Maximum number of simultaneous connections allowed per user",
+					Optional:         true,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				},
+				"enable_session_affinity": {
+					Type:        schema.TypeBool,
+					Description: "Enable session affinity",
+					Optional:    true,
+				},
+			}),
 		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importConnectionGroupState,
 		},
 	}
+
+	resource.StateUpgraders = []schema.StateUpgrader{
+		typedParametersStateUpgrader(resource, stateSchemasV0["group"]),
+	}
+
+	return resource
 }
 
 func resourceConnectionGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -101,7 +100,13 @@ func resourceConnectionGroupCreate(ctx context.Context, d *schema.ResourceData,
 		return check
 	}
 
-	err := client.CreateConnectionGroup(&group)
+	attributes, err := buildObjectAttributes(d, client, "", group.Attributes)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.WriteObject(http.MethodPost, "connectionGroups", &group, attributes, &group)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -118,7 +123,7 @@ func resourceConnectionGroupCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceConnectionGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -136,16 +141,22 @@ func resourceConnectionGroupRead(ctx context.Context, d *schema.ResourceData, m
 		return check
 	}
 
+	err = setExtraAttributes(d, client, connectionGroupObjectPath(identifier), typedConnectionGroupAttributes, false)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(identifier)
 
 	return diags
 }
 
 func resourceConnectionGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 	var diags diag.Diagnostics
 
-	if d.HasChanges("name", "identifier", "parent_identifier", "type", "attributes") {
+	if d.HasChanges("name", "identifier", "parent_identifier", "type", "attributes", "extra_attributes") {
 		validate := validateConnectionGroup(d, client)
 
 		if validate.HasError() {
@@ -158,7 +169,13 @@ func resourceConnectionGroupUpdate(ctx context.Context, d *schema.ResourceData,
 			return check
 		}
 
-		err := client.UpdateConnectionGroup(&group)
+		attributes, err := buildObjectAttributes(d, client, connectionGroupObjectPath(group.Identifier), group.Attributes)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.WriteObject(http.MethodPut, connectionGroupObjectPath(group.Identifier), &group, attributes, nil)
 
 		if err != nil {
 			return diag.FromErr(err)
@@ -178,7 +195,7 @@ func resourceConnectionGroupUpdate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceConnectionGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -204,13 +221,14 @@ func convertResourceDataToGuacConnectionGroup(d *schema.ResourceData) (types.Gua
 	group.Name = d.Get("name").(string)
 	group.Type = strings.ToUpper(d.Get("type").(string))
 
+	config := d.GetRawConfig()
 	attributeList := d.Get("attributes").([]interface{})
 
 	if len(attributeList) > 0 {
 		attributes := attributeList[0].(map[string]interface{})
 		group.Attributes = types.GuacConnectionGroupAttributes{
-			MaxConnections:        attributes["max_connections"].(string),
-			MaxConnectionsPerUser: attributes["max_connections_per_user"].(string),
+			MaxConnections:        configuredIntToString(attributes["max_connections"].(int), attributeConfigured(config, "max_connections")),
+			MaxConnectionsPerUser: configuredIntToString(attributes["max_connections_per_user"].(int), attributeConfigured(config, "max_connections_per_user")),
 			EnableSessionAffinity: boolToString(attributes["enable_session_affinity"].(bool)),
 		}
 	}
@@ -225,8 +243,8 @@ func convertGuacConnectionGroupToResourceData(d *schema.ResourceData, group *typ
 	d.Set("type", group.Type)
 
 	attributes := map[string]interface{}{
-		"max_connections":          group.Attributes.MaxConnections,
-		"max_connections_per_user": group.Attributes.MaxConnectionsPerUser,
+		"max_connections":          stringToInt(group.Attributes.MaxConnections),
+		"max_connections_per_user": stringToInt(group.Attributes.MaxConnectionsPerUser),
 		"enable_session_affinity":  stringToBool(group.Attributes.EnableSessionAffinity),
 	}
 	var attributeList []map[string]interface{}
@@ -238,7 +256,7 @@ func convertGuacConnectionGroupToResourceData(d *schema.ResourceData, group *typ
 	return nil
 }
 
-func validateConnectionGroup(d *schema.ResourceData, client *guac.Client) diag.Diagnostics {
+func validateConnectionGroup(d *schema.ResourceData, client *guacamoleClient) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	connectionGroupInterface := types.GuacConnectionGroup{}
@@ -265,30 +283,5 @@ func validateConnectionGroup(d *schema.ResourceData, client *guac.Client) diag.D
 		}
 	}
 
-	// validate attributes
-	attributeList := d.Get("attributes").([]interface{})
-
-	stringIntAttributes := []string{
-		"max_connections",
-		"max_connections_per_user",
-	}
-
-	if len(attributeList) > 0 {
-		attributes := attributeList[0].(map[string]interface{})
-		// validate string integer values
-		for _, v := range stringIntAttributes {
-			if attributes[v].(string) != "" {
-				_, err := strconv.Atoi(attributes[v].(string))
-				if err != nil {
-					diags = append(diags, diag.Diagnostic{
-						Severity: diag.Error,
-						Summary:  "Invalid entry",
-						Detail:   fmt.Sprintf("Expected string integer for attribute key: %s but was unable to convert: %s to integer", v, attributes[v].(string)),
-					})
-				}
-			}
-		}
-	}
-
 	return diags
 }
@@ -1,10 +1,12 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -20,7 +22,7 @@ var testProviderConnectionGroup = map[string]interface{}{
 }
 
 func TestAccGuacamoleConnectionGroupBasic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -59,3 +61,73 @@ func testAccCheckGuacamoleConnectionGroupExists(resourceName string) resource.Te
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionGroupFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	parentState := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "fakeParentGroup",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+
+	config := map[string]interface{}{
+		"name":              "fakeGroup",
+		"parent_identifier": parentState.ID,
+		"type":              "BALANCING",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections":          4,
+				"max_connections_per_user": 0,
+				"enable_session_affinity":  true,
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionGroup(), nil, config, client)
+
+	group, ok := f.connectionGroups[state.ID]
+	if !ok {
+		t.Fatalf("connection group %s was not created", state.ID)
+	}
+	if value := group.attributes["max-connections-per-user"]; value == nil || *value != "0" {
+		t.Errorf("expected the configured max-connections-per-user 0, got %v", value)
+	}
+	if group.parentIdentifier != parentState.ID || group.groupType != "BALANCING" {
+		t.Errorf("expected BALANCING group below %s, got %s group below %s", parentState.ID, group.groupType, group.parentIdentifier)
+	}
+
+	ds := dataSourceConnectionGroup()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeParentGroup/fakeGroup",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection group by path: %v", diags)
+	}
+	if d.Id() != state.ID {
+		t.Errorf("expected connection group %s, got %s", state.ID, d.Id())
+	}
+
+	config["parent_identifier"] = "ROOT"
+	state = testFakeApply(t, guacamoleConnectionGroup(), state, config, client)
+
+	if group.parentIdentifier != "ROOT" {
+		t.Errorf("expected group to be moved to ROOT, got %s", group.parentIdentifier)
+	}
+
+	// removing the attributes block unsets the attributes
+	delete(config, "attributes")
+	state = testFakeApply(t, guacamoleConnectionGroup(), state, config, client)
+
+	for _, name := range []string{"max-connections", "max-connections-per-user", "enable-session-affinity"} {
+		if value, ok := group.attributes[name]; ok {
+			t.Errorf("expected %s attribute to be unset, got %s", name, *value)
+		}
+	}
+
+	testFakeDestroy(t, guacamoleConnectionGroup(), state, client)
+	testFakeDestroy(t, guacamoleConnectionGroup(), parentState, client)
+
+	if len(f.connectionGroups) != 0 {
+		t.Errorf("expected no connection groups to remain, got %d", len(f.connectionGroups))
+	}
+}
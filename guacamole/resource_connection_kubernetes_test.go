@@ -1,10 +1,13 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -45,10 +48,16 @@ func TestAccGuacamoleConnectionKubernetesBasic(t *testing.T) {
 			"recording_exclude_mouse":     true,
 			"recording_include_keys":      true,
 			"recording_auto_create_path":  true,
+			"exec_command":                "/bin/sh",
+			"disable_copy":                true,
+			"disable_paste":               true,
+			"clipboard_encoding":          "UTF-8",
+			"terminal_type":               "xterm",
+			"recording_exclude_touch":     true,
 		},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -88,6 +97,12 @@ func TestAccGuacamoleConnectionKubernetesBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.recording_exclude_mouse", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["recording_exclude_mouse"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.recording_include_keys", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["recording_include_keys"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.recording_auto_create_path", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["recording_auto_create_path"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.exec_command", testProviderConnectionKubernetes["parameters"].(map[string]interface{})["exec_command"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.disable_copy", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["disable_copy"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.disable_paste", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["disable_paste"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.clipboard_encoding", testProviderConnectionKubernetes["parameters"].(map[string]interface{})["clipboard_encoding"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.terminal_type", testProviderConnectionKubernetes["parameters"].(map[string]interface{})["terminal_type"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_kubernetes.new", "parameters.0.recording_exclude_touch", boolToString(testProviderConnectionKubernetes["parameters"].(map[string]interface{})["recording_exclude_touch"].(bool))),
 				),
 			},
 		},
@@ -115,3 +130,77 @@ func testAccCheckGuacamoleConnectionKubernetesExists(n string) resource.TestChec
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionKubernetesFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionKubernetes",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "kubernetes.example.com",
+				"port":     6443,
+				"use_ssl":  true,
+			},
+		},
+		"container": []interface{}{
+			map[string]interface{}{
+				"namespace": "apps",
+				"pod":       "web-0",
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionKubernetes(), nil, config, client)
+
+	connection, ok := f.connections[state.ID]
+	if !ok {
+		t.Fatalf("connection %s was not created", state.ID)
+	}
+	expected := map[string]string{
+		"hostname":  "kubernetes.example.com",
+		"port":      "6443",
+		"use-ssl":   "true",
+		"namespace": "apps",
+		"pod":       "web-0",
+	}
+	if connection.protocol != "kubernetes" || !reflect.DeepEqual(connection.parameters, expected) {
+		t.Errorf("expected kubernetes parameters %v, got %s parameters %v", expected, connection.protocol, connection.parameters)
+	}
+
+	config["container"] = []interface{}{
+		map[string]interface{}{
+			"pod":       "web-1",
+			"container": "nginx",
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionKubernetes(), state, config, client)
+
+	if connection.parameters["pod"] != "web-1" || connection.parameters["container"] != "nginx" {
+		t.Errorf("expected pod web-1 and container nginx, got %v", connection.parameters)
+	}
+	if _, ok := connection.parameters["namespace"]; ok {
+		t.Errorf("expected namespace to be removed, got %v", connection.parameters)
+	}
+
+	ds := dataSourceConnectionKubernetes()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeConnectionKubernetes",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection by path: %v", diags)
+	}
+	if d.Id() != state.ID || d.Get("container.0.pod").(string) != "web-1" {
+		t.Errorf("expected connection %s with pod web-1, got %s with pod %s", state.ID, d.Id(), d.Get("container.0.pod").(string))
+	}
+
+	testFakeDestroy(t, guacamoleConnectionKubernetes(), state, client)
+
+	if len(f.connections) != 0 {
+		t.Errorf("expected no connections to remain, got %d", len(f.connections))
+	}
+}
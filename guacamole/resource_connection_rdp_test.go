@@ -1,11 +1,15 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
 func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
@@ -48,7 +52,7 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 			"disable_copy":                 true,
 			"disable_paste":                true,
 			"console_audio":                true,
-			"disable_audio":                true,
+			"disable_audio":                false,
 			"enable_audio_input":           true,
 			"enable_printing":              true,
 			"printer_name":                 "printer name",
@@ -58,7 +62,14 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 			"disable_file_upload":          true,
 			"drive_path":                   "drive path",
 			"create_drive_path":            true,
-			"static_channels":              "channel-1, channel-2",
+			"disable_server_input":         true,
+			"disable_display_resize":       true,
+			"force_lossless":               true,
+			"normalize_clipboard":          "windows",
+			"enable_touch":                 true,
+			"recording_exclude_touch":      true,
+			"recording_write_existing":     true,
+			"static_channels":              []string{"channel-1", "channel-2"},
 			"enable_wallpaper":             true,
 			"enable_theming":               true,
 			"enable_font_smoothing":        true,
@@ -100,7 +111,7 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 		},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -143,6 +154,13 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.disable_copy", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["disable_copy"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.disable_paste", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["disable_paste"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_audio_input", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_audio_input"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.disable_server_input", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["disable_server_input"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.disable_display_resize", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["disable_display_resize"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.force_lossless", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["force_lossless"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.normalize_clipboard", testProviderConnectionRDP["parameters"].(map[string]interface{})["normalize_clipboard"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_touch", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_touch"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.recording_exclude_touch", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["recording_exclude_touch"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.recording_write_existing", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["recording_write_existing"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_printing", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_printing"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.printer_name", testProviderConnectionRDP["parameters"].(map[string]interface{})["printer_name"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_drive", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_drive"].(bool))),
@@ -151,7 +169,7 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.disable_file_upload", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["disable_file_upload"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.drive_path", testProviderConnectionRDP["parameters"].(map[string]interface{})["drive_path"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.create_drive_path", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["create_drive_path"].(bool))),
-					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.static_channels", testProviderConnectionRDP["parameters"].(map[string]interface{})["static_channels"].(string)),
+					testAccCheckTestSliceVals("guacamole_connection_rdp.new", "parameters.0.static_channels", testProviderConnectionRDP["parameters"].(map[string]interface{})["static_channels"].([]string)),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_wallpaper", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_wallpaper"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_theming", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_theming"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_rdp.new", "parameters.0.enable_font_smoothing", boolToString(testProviderConnectionRDP["parameters"].(map[string]interface{})["enable_font_smoothing"].(bool))),
@@ -196,6 +214,105 @@ func TestAccGuacamoleConnectionRDPBasic(t *testing.T) {
 	})
 }
 
+func TestValidateConnectionRDP(t *testing.T) {
+	cases := map[string]struct {
+		parameters map[string]interface{}
+		valid      bool
+	}{
+		"gateway": {
+			parameters: map[string]interface{}{
+				"gateway_hostname": "gateway.example.com",
+				"gateway_port":     8443,
+				"gateway_domain":   "EXAMPLE",
+			},
+			valid: true,
+		},
+		"gateway without hostname": {
+			parameters: map[string]interface{}{
+				"gateway_port": 8443,
+			},
+		},
+		"printer without printing": {
+			parameters: map[string]interface{}{
+				"printer_name": "printer",
+			},
+		},
+		"audio input without audio": {
+			parameters: map[string]interface{}{
+				"disable_audio":      true,
+				"enable_audio_input": true,
+			},
+		},
+		"newer keyboard layout": {
+			parameters: map[string]interface{}{
+				"keyboard_layout":     "pt-pt-qwerty",
+				"normalize_clipboard": "unix",
+			},
+			valid: true,
+		},
+		"invalid clipboard normalization": {
+			parameters: map[string]interface{}{
+				"normalize_clipboard": "mac",
+			},
+		},
+	}
+
+	for name, c := range cases {
+		c.parameters["hostname"] = "rdp.example.com"
+		c.parameters["username"] = "user"
+		config := map[string]interface{}{
+			"name":       "rdp",
+			"parameters": []interface{}{c.parameters},
+		}
+		// restricted values are validated by the schema, dependent settings by validateConnectionRDP
+		r := guacamoleConnectionRDP()
+		diags := r.Validate(terraform.NewResourceConfigRaw(config))
+		d := schema.TestResourceDataRaw(t, r.Schema, config)
+		diags = append(diags, validateConnectionRDP(d)...)
+		if diags.HasError() == c.valid {
+			t.Errorf("%s: expected valid %t, got %v", name, c.valid, diags)
+		}
+	}
+}
+
+func TestConnectionRDPGuacdParameters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionRDP().Schema, map[string]interface{}{
+		"name": "rdp",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":            "rdp.example.com",
+				"force_lossless":      true,
+				"normalize_clipboard": "windows",
+			},
+		},
+	})
+
+	_, parameters, diags := convertResourceDataToGuacConnection(d, "rdp")
+	if diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	expected := map[string]string{
+		"force-lossless":      "true",
+		"normalize-clipboard": "windows",
+	}
+	if !reflect.DeepEqual(parameters, expected) {
+		t.Fatalf("expected guacd parameters %v, got %v", expected, parameters)
+	}
+
+	read := schema.TestResourceDataRaw(t, guacamoleConnectionRDP().Schema, map[string]interface{}{})
+	connection := types.GuacConnection{Parameters: types.GuacConnectionParameters{Hostname: "rdp.example.com"}}
+	if diags := convertGuacConnectionToResourceData(read, &connection, parameters, "rdp"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	readParameters := mergeParameterSections(read, connectionParameterSections("rdp"))[0].(map[string]interface{})
+	if !readParameters["force_lossless"].(bool) || readParameters["normalize_clipboard"].(string) != "windows" {
+		t.Fatalf("expected guacd parameters to be read back, got %v", readParameters)
+	}
+	if readParameters["enable_touch"].(bool) {
+		t.Fatalf("expected unset guacd parameters to be false")
+	}
+}
+
 func testAccCheckGuacamoleConnectionRDPConfigBasic(connection string) string {
 	return fmt.Sprintf(`
 	resource "guacamole_connection_rdp" "new" %s
@@ -217,3 +334,113 @@ func testAccCheckGuacamoleConnectionRDPExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionRDPFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionRDP",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "rdp.example.com",
+				"port":     3390,
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username":      "user",
+				"password":      "password",
+				"security_mode": "nla",
+			},
+		},
+		"display": []interface{}{
+			map[string]interface{}{
+				"width":  1920,
+				"height": 1080,
+			},
+		},
+		"device_redirection": []interface{}{
+			map[string]interface{}{
+				"static_channels": []interface{}{"channel-1", "channel-2"},
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionRDP(), nil, config, client)
+
+	connection, ok := f.connections[state.ID]
+	if !ok {
+		t.Fatalf("connection %s was not created", state.ID)
+	}
+	expected := map[string]string{
+		"hostname":        "rdp.example.com",
+		"port":            "3390",
+		"username":        "user",
+		"password":        "password",
+		"security":        "nla",
+		"width":           "1920",
+		"height":          "1080",
+		"static-channels": "channel-1,channel-2",
+	}
+	if connection.protocol != "rdp" || !reflect.DeepEqual(connection.parameters, expected) {
+		t.Errorf("expected rdp parameters %v, got %s parameters %v", expected, connection.protocol, connection.parameters)
+	}
+
+	delete(config, "display")
+	state = testFakeApply(t, guacamoleConnectionRDP(), state, config, client)
+
+	if _, ok := connection.parameters["width"]; ok {
+		t.Errorf("expected width to be removed, got %v", connection.parameters)
+	}
+
+	ds := dataSourceConnectionRDP()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeConnectionRDP",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection by path: %v", diags)
+	}
+	if d.Id() != state.ID || d.Get("network.0.port").(int) != 3390 {
+		t.Errorf("expected connection %s with port 3390, got %s with port %d", state.ID, d.Id(), d.Get("network.0.port").(int))
+	}
+
+	testFakeDestroy(t, guacamoleConnectionRDP(), state, client)
+
+	if len(f.connections) != 0 {
+		t.Errorf("expected no connections to remain, got %d", len(f.connections))
+	}
+}
+
+func TestGuacamoleConnectionRDPFakeValidation(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+	r := guacamoleConnectionRDP()
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionRDP",
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "rdp.example.com",
+			},
+		},
+		"device_redirection": []interface{}{
+			map[string]interface{}{
+				"printer_name": "printer",
+			},
+		},
+	}
+	diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), client)
+	if err != nil {
+		t.Fatalf("unable to plan: %s", err)
+	}
+	_, diags := r.Apply(context.Background(), nil, diff, client)
+	if !diags.HasError() || diags[0].Detail != "Parameter printer_name requires enable_printing to be true" {
+		t.Fatalf("expected the rdp settings to be validated before writing the connection, got %v", diags)
+	}
+	if len(f.connections) != 0 {
+		t.Fatalf("expected no connection to be created, got %d", len(f.connections))
+	}
+}
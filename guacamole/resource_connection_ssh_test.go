@@ -1,10 +1,13 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -22,7 +25,7 @@ func TestAccGuacamoleConnectionSSHBasic(t *testing.T) {
 		},
 		"parameters": map[string]interface{}{
 			"hostname":                    "hostname.example.com",
-			"port":                        "22",
+			"port":                        "2022",
 			"public_host_key":             "public host key",
 			"username":                    "user",
 			"password":                    "password",
@@ -57,10 +60,16 @@ func TestAccGuacamoleConnectionSSHBasic(t *testing.T) {
 			"wol_mac_address":             "00:11:22:33:44",
 			"wol_broadcast_address":       "255.255.255.254",
 			"wol_boot_wait_time":          "5",
+			"public_key":                  "ssh-rsa-cert-v01@openssh.com certificate",
+			"disable_server_input":        true,
+			"clipboard_encoding":          "UTF-8",
+			"locale":                      "en_US",
+			"recording_exclude_touch":     true,
+			"recording_write_existing":    true,
 		},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -112,6 +121,195 @@ func TestAccGuacamoleConnectionSSHBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.wol_mac_address", testProviderConnectionSSH["parameters"].(map[string]interface{})["wol_mac_address"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.wol_broadcast_address", testProviderConnectionSSH["parameters"].(map[string]interface{})["wol_broadcast_address"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.wol_boot_wait_time", testProviderConnectionSSH["parameters"].(map[string]interface{})["wol_boot_wait_time"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.public_key", testProviderConnectionSSH["parameters"].(map[string]interface{})["public_key"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.disable_server_input", boolToString(testProviderConnectionSSH["parameters"].(map[string]interface{})["disable_server_input"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.clipboard_encoding", testProviderConnectionSSH["parameters"].(map[string]interface{})["clipboard_encoding"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.locale", testProviderConnectionSSH["parameters"].(map[string]interface{})["locale"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.recording_exclude_touch", boolToString(testProviderConnectionSSH["parameters"].(map[string]interface{})["recording_exclude_touch"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.new", "parameters.0.recording_write_existing", boolToString(testProviderConnectionSSH["parameters"].(map[string]interface{})["recording_write_existing"].(bool))),
+				),
+			},
+		},
+	})
+}
+
+func TestConnectionSSHParametersRoundTrip(t *testing.T) {
+	parameters := map[string]interface{}{
+		"hostname":        "hostname.example.com",
+		"port":            2200,
+		"public_host_key": "public host key",
+		"username":        "user",
+		"password":        "password",
+		"private_key":     "super secret private key",
+		"passphrase":      "gigem",
+		"public_key":      "ssh-rsa-cert-v01@openssh.com certificate",
+		"color_scheme":    "",
+		"custom_color_scheme": []interface{}{
+			map[string]interface{}{
+				"foreground": "rgb:ff/ff/ff",
+				"background": "color0",
+				"palette": map[string]interface{}{
+					"0":   "rgb:00/00/00",
+					"255": "rgb:12/34/56",
+				},
+			},
+		},
+		"font_name":                   "Helvetica, sans-serif",
+		"font_size":                   13,
+		"max_scrollback_size":         200,
+		"readonly":                    true,
+		"disable_server_input":        true,
+		"disable_copy":                true,
+		"disable_paste":               true,
+		"clipboard_encoding":          "UTF-8",
+		"execute_command":             "run this",
+		"locale":                      "en_US",
+		"timezone":                    "America/Chicago",
+		"server_keepalive":            20,
+		"backspace":                   "127",
+		"terminal_type":               "vt100",
+		"typescript_path":             "typescript path",
+		"typescript_name":             "typescript name",
+		"typescript_auto_create_path": true,
+		"recording_path":              "recording path",
+		"recording_name":              "recording name",
+		"recording_exclude_output":    true,
+		"recording_exclude_mouse":     true,
+		"recording_exclude_touch":     true,
+		"recording_include_keys":      true,
+		"recording_auto_create_path":  true,
+		"recording_write_existing":    true,
+		"sftp_enable":                 true,
+		"sftp_root_directory":         "sftp/root/directory",
+		"sftp_disable_file_download":  true,
+		"sftp_disable_file_upload":    true,
+		"wol_send_packet":             true,
+		"wol_mac_address":             "00:11:22:33:44",
+		"wol_broadcast_address":       "255.255.255.254",
+		"wol_boot_wait_time":          5,
+	}
+
+	// every parameter of the schema is covered by the round trip
+	elem := guacamoleConnectionSSH().Schema["parameters"].Elem.(*schema.Resource)
+	for k := range elem.Schema {
+		if _, ok := parameters[k]; !ok {
+			t.Fatalf("parameter %s is missing from the round trip test", k)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionSSH().Schema, map[string]interface{}{
+		"name":              "ssh",
+		"parent_identifier": "ROOT",
+		"parameters":        []interface{}{parameters},
+	})
+
+	connection, guacdParameters, diags := convertResourceDataToGuacConnection(d, "ssh")
+	if diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if connection.Parameters.ColorScheme != "foreground: rgb:ff/ff/ff; background: color0; color0: rgb:00/00/00; color255: rgb:12/34/56" {
+		t.Fatalf("unexpected color scheme %q", connection.Parameters.ColorScheme)
+	}
+	expected := map[string]string{
+		"public-key":               "ssh-rsa-cert-v01@openssh.com certificate",
+		"disable-server-input":     "true",
+		"recording-exclude-touch":  "true",
+		"recording-write-existing": "true",
+	}
+	if !reflect.DeepEqual(guacdParameters, expected) {
+		t.Fatalf("expected guacd parameters %v, got %v", expected, guacdParameters)
+	}
+
+	read := schema.TestResourceDataRaw(t, guacamoleConnectionSSH().Schema, map[string]interface{}{})
+	if diags := convertGuacConnectionToResourceData(read, &connection, guacdParameters, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+
+	roundTrip := mergeParameterSections(read, connectionParameterSections("ssh"))[0].(map[string]interface{})
+	if !reflect.DeepEqual(roundTrip, parameters) {
+		for k, v := range parameters {
+			if !reflect.DeepEqual(roundTrip[k], v) {
+				t.Errorf("parameter %s: expected %#v, got %#v", k, v, roundTrip[k])
+			}
+		}
+		t.FailNow()
+	}
+}
+
+func TestAccGuacamoleConnectionSSHParameterSections(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckGuacamoleConnectionSSHConfigParameterSections,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleConnectionSSHExists("guacamole_connection_ssh.sections"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "parameters.#", "0"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "network.0.hostname", "hostname.example.com"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "network.0.port", "2222"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "authentication.0.username", "user"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "display.0.font_size", "13"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "sftp.0.enable", "true"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.sections", "sftp.0.root_directory", "/home/user"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckGuacamoleConnectionSSHConfigParameterSections = `
+	resource "guacamole_connection_ssh" "sections" {
+	  name              = "testProviderConnectionSSHSections"
+	  parent_identifier = "ROOT"
+	  network {
+	    hostname = "hostname.example.com"
+	    port     = 2222
+	  }
+	  authentication {
+	    username = "user"
+	  }
+	  display {
+	    font_size = 13
+	  }
+	  sftp {
+	    enable         = true
+	    root_directory = "/home/user"
+	  }
+	}
+	`
+
+func TestAccGuacamoleConnectionSSHCustomColorScheme(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckGuacamoleConnectionSSHConfigCustomColorScheme(`
+	    custom_color_scheme {
+	      foreground = "rgb:ff/ff/ff"
+	      background = "color0"
+	      palette = {
+	        0  = "rgb:00/00/00"
+	        15 = "rgb:fe/fe/fe"
+	      }
+	    }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleConnectionSSHExists("guacamole_connection_ssh.colors"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.color_scheme", ""),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.font_size", "13"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.custom_color_scheme.0.foreground", "rgb:ff/ff/ff"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.custom_color_scheme.0.background", "color0"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.custom_color_scheme.0.palette.%", "2"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.custom_color_scheme.0.palette.15", "rgb:fe/fe/fe"),
+				),
+			},
+			{
+				Config: testAccCheckGuacamoleConnectionSSHConfigCustomColorScheme(`
+	    color_scheme = "gray-black"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.color_scheme", "gray-black"),
+					resource.TestCheckResourceAttr("guacamole_connection_ssh.colors", "parameters.0.custom_color_scheme.#", "0"),
 				),
 			},
 		},
@@ -124,6 +322,20 @@ func testAccCheckGuacamoleConnectionSSHConfigBasic(connection string) string {
 	`, connection)
 }
 
+func testAccCheckGuacamoleConnectionSSHConfigCustomColorScheme(colors string) string {
+	return fmt.Sprintf(`
+	resource "guacamole_connection_ssh" "colors" {
+	  name              = "testProviderConnectionSSHColors"
+	  parent_identifier = "ROOT"
+	  parameters {
+	    hostname  = "hostname.example.com"
+	    username  = "user"
+	    font_size = 13%s
+	  }
+	}
+	`, colors)
+}
+
 func testAccCheckGuacamoleConnectionSSHExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -139,3 +351,152 @@ func testAccCheckGuacamoleConnectionSSHExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionSSHFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	groupState := testFakeApply(t, guacamoleConnectionGroup(), nil, map[string]interface{}{
+		"name":              "fakeGroup",
+		"parent_identifier": "ROOT",
+		"type":              "ORGANIZATIONAL",
+	}, client)
+
+	config := map[string]interface{}{
+		"name":              "fakeConnectionSSH",
+		"parent_identifier": groupState.ID,
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "hostname.example.com",
+				"port":     2222,
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "user",
+				"password": "password",
+			},
+		},
+		"display": []interface{}{
+			map[string]interface{}{
+				"font_size": 13,
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionSSH(), nil, config, client)
+
+	connection, ok := f.connections[state.ID]
+	if !ok {
+		t.Fatalf("connection %s was not created", state.ID)
+	}
+	expected := map[string]string{
+		"hostname":  "hostname.example.com",
+		"port":      "2222",
+		"username":  "user",
+		"password":  "password",
+		"font-size": "13",
+	}
+	if !reflect.DeepEqual(connection.parameters, expected) {
+		t.Errorf("expected parameters %v, got %v", expected, connection.parameters)
+	}
+
+	config["authentication"] = []interface{}{
+		map[string]interface{}{
+			"username": "other",
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionSSH(), state, config, client)
+
+	if connection.parameters["username"] != "other" {
+		t.Errorf("expected username other, got %s", connection.parameters["username"])
+	}
+	if _, ok := connection.parameters["password"]; ok {
+		t.Errorf("expected password to be removed")
+	}
+
+	ds := dataSourceConnectionSSH()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeGroup/fakeConnectionSSH",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection by path: %v", diags)
+	}
+	if d.Id() != state.ID || d.Get("network.0.port").(int) != 2222 {
+		t.Errorf("expected connection %s with port 2222, got %s with port %d", state.ID, d.Id(), d.Get("network.0.port").(int))
+	}
+
+	testFakeDestroy(t, guacamoleConnectionSSH(), state, client)
+	testFakeDestroy(t, guacamoleConnectionGroup(), groupState, client)
+
+	if len(f.connections) != 0 || len(f.connectionGroups) != 0 {
+		t.Errorf("expected no connections to remain, got %d connections and %d connection groups", len(f.connections), len(f.connectionGroups))
+	}
+}
+
+func TestGuacamoleConnectionSSHFakeZeroIntegers(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	// max_connections and scrollback are configured as 0, which differs from leaving them unset
+	config := map[string]interface{}{
+		"name": "fakeConnectionSSH",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 0,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "hostname.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "user",
+			},
+		},
+		"display": []interface{}{
+			map[string]interface{}{
+				"max_scrollback_size": 0,
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionSSH(), nil, config, client)
+
+	connection := f.connections[state.ID]
+	if value := connection.attributes["max-connections"]; value == nil || *value != "0" {
+		t.Errorf("expected max-connections 0, got %v", value)
+	}
+	if _, ok := connection.attributes["weight"]; ok {
+		t.Errorf("expected the weight that isn't configured to be left unset")
+	}
+	if connection.parameters["scrollback"] != "0" {
+		t.Errorf("expected scrollback 0, got %v", connection.parameters)
+	}
+	if _, ok := connection.parameters["port"]; ok {
+		t.Errorf("expected the port that isn't configured to be left unset, got %v", connection.parameters)
+	}
+
+	// changing a value to 0 sends 0
+	config["attributes"] = []interface{}{
+		map[string]interface{}{
+			"max_connections":          0,
+			"max_connections_per_user": 3,
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionSSH(), state, config, client)
+	config["attributes"] = []interface{}{
+		map[string]interface{}{
+			"max_connections":          0,
+			"max_connections_per_user": 0,
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionSSH(), state, config, client)
+
+	if value := connection.attributes["max-connections-per-user"]; value == nil || *value != "0" {
+		t.Errorf("expected max-connections-per-user 0, got %v", value)
+	}
+}
@@ -1,10 +1,14 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -51,10 +55,15 @@ func TestAccGuacamoleConnectionTelnetBasic(t *testing.T) {
 			"wol_mac_address":             "00:11:22:33:44",
 			"wol_broadcast_address":       "255.255.255.254",
 			"wol_boot_wait_time":          "5",
+			"disable_server_input":        true,
+			"clipboard_encoding":          "UTF-8",
+			"locale":                      "en_US",
+			"timezone":                    "America/Chicago",
+			"recording_exclude_touch":     true,
 		},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -100,12 +109,37 @@ func TestAccGuacamoleConnectionTelnetBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.wol_mac_address", testProviderConnectionTelnet["parameters"].(map[string]interface{})["wol_mac_address"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.wol_broadcast_address", testProviderConnectionTelnet["parameters"].(map[string]interface{})["wol_broadcast_address"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.wol_boot_wait_time", testProviderConnectionTelnet["parameters"].(map[string]interface{})["wol_boot_wait_time"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.disable_server_input", boolToString(testProviderConnectionTelnet["parameters"].(map[string]interface{})["disable_server_input"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.clipboard_encoding", testProviderConnectionTelnet["parameters"].(map[string]interface{})["clipboard_encoding"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.locale", testProviderConnectionTelnet["parameters"].(map[string]interface{})["locale"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.timezone", testProviderConnectionTelnet["parameters"].(map[string]interface{})["timezone"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_telnet.new", "parameters.0.recording_exclude_touch", boolToString(testProviderConnectionTelnet["parameters"].(map[string]interface{})["recording_exclude_touch"].(bool))),
 				),
 			},
 		},
 	})
 }
 
+func TestValidatePOSIXRegex(t *testing.T) {
+	elem := guacamoleConnectionTelnet().Schema["parameters"].Elem.(*schema.Resource)
+	for _, k := range []string{"username_regex", "password_regex", "login_success_regex", "login_failure_regex"} {
+		if elem.Schema[k].ValidateDiagFunc == nil {
+			t.Fatalf("expected %s to be validated", k)
+		}
+	}
+
+	for _, expression := range []string{".*[Ll]ogin:", "[[:alpha:]]+ password:", "^Welcome"} {
+		if diags := validatePOSIXRegex(expression, cty.Path{}); diags.HasError() {
+			t.Errorf("expected %q to be valid, got %v", expression, diags)
+		}
+	}
+	for _, expression := range []string{"[unclosed", "(?i)login:", "(unclosed"} {
+		if diags := validatePOSIXRegex(expression, cty.Path{}); !diags.HasError() {
+			t.Errorf("expected %q to be invalid", expression)
+		}
+	}
+}
+
 func testAccCheckGuacamoleConnectionTelnetConfigBasic(connection string) string {
 	return fmt.Sprintf(`
 	resource "guacamole_connection_telnet" "new" %s
@@ -127,3 +161,82 @@ func testAccCheckGuacamoleConnectionTelnetExists(n string) resource.TestCheckFun
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionTelnetFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionTelnet",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "telnet.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username":       "user",
+				"password":       "password",
+				"password_regex": "[Pp]assword:",
+			},
+		},
+		"display": []interface{}{
+			map[string]interface{}{
+				"font_size":           12,
+				"max_scrollback_size": 2048,
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionTelnet(), nil, config, client)
+
+	connection, ok := f.connections[state.ID]
+	if !ok {
+		t.Fatalf("connection %s was not created", state.ID)
+	}
+	expected := map[string]string{
+		"hostname":       "telnet.example.com",
+		"username":       "user",
+		"password":       "password",
+		"password-regex": "[Pp]assword:",
+		"font-size":      "12",
+		"scrollback":     "2048",
+	}
+	if connection.protocol != "telnet" || !reflect.DeepEqual(connection.parameters, expected) {
+		t.Errorf("expected telnet parameters %v, got %s parameters %v", expected, connection.protocol, connection.parameters)
+	}
+
+	config["display"] = []interface{}{
+		map[string]interface{}{
+			"font_size": 14,
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionTelnet(), state, config, client)
+
+	if connection.parameters["font-size"] != "14" {
+		t.Errorf("expected font size 14, got %v", connection.parameters)
+	}
+	if _, ok := connection.parameters["scrollback"]; ok {
+		t.Errorf("expected scrollback to be removed, got %v", connection.parameters)
+	}
+
+	ds := dataSourceConnectionTelnet()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeConnectionTelnet",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection by path: %v", diags)
+	}
+	if d.Id() != state.ID || d.Get("display.0.font_size").(int) != 14 {
+		t.Errorf("expected connection %s with font size 14, got %s with font size %d", state.ID, d.Id(), d.Get("display.0.font_size").(int))
+	}
+
+	testFakeDestroy(t, guacamoleConnectionTelnet(), state, client)
+
+	if len(f.connections) != 0 {
+		t.Errorf("expected no connections to remain, got %d", len(f.connections))
+	}
+}
@@ -1,11 +1,16 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
 func TestAccGuacamoleConnectionVNCBasic(t *testing.T) {
@@ -30,6 +35,13 @@ func TestAccGuacamoleConnectionVNCBasic(t *testing.T) {
 			"cursor":                     "local",
 			"color_depth":                "24",
 			"clipboard_encoding":         "ISO8859-1",
+			"encodings":                  []string{"tight", "zrle", "raw"},
+			"autoretry":                  "3",
+			"compress_level":             "6",
+			"quality_level":              "8",
+			"force_lossless":             true,
+			"disable_display_resize":     true,
+			"recording_exclude_touch":    true,
 			"disable_copy":               true,
 			"disable_paste":              true,
 			"destination_host":           "destination host",
@@ -60,7 +72,7 @@ func TestAccGuacamoleConnectionVNCBasic(t *testing.T) {
 		},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -108,6 +120,14 @@ func TestAccGuacamoleConnectionVNCBasic(t *testing.T) {
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.sftp_keepalive_interval", testProviderConnectionVNC["parameters"].(map[string]interface{})["sftp_keepalive_interval"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.sftp_disable_file_download", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["sftp_disable_file_download"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.sftp_disable_file_upload", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["sftp_disable_file_upload"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.encodings.#", "3"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.encodings.0", "tight"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.autoretry", testProviderConnectionVNC["parameters"].(map[string]interface{})["autoretry"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.compress_level", testProviderConnectionVNC["parameters"].(map[string]interface{})["compress_level"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.quality_level", testProviderConnectionVNC["parameters"].(map[string]interface{})["quality_level"].(string)),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.force_lossless", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["force_lossless"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.disable_display_resize", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["disable_display_resize"].(bool))),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.recording_exclude_touch", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["recording_exclude_touch"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.wol_send_packet", boolToString(testProviderConnectionVNC["parameters"].(map[string]interface{})["wol_send_packet"].(bool))),
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.wol_mac_address", testProviderConnectionVNC["parameters"].(map[string]interface{})["wol_mac_address"].(string)),
 					resource.TestCheckResourceAttr("guacamole_connection_vnc.new", "parameters.0.wol_broadcast_address", testProviderConnectionVNC["parameters"].(map[string]interface{})["wol_broadcast_address"].(string)),
@@ -118,6 +138,172 @@ func TestAccGuacamoleConnectionVNCBasic(t *testing.T) {
 	})
 }
 
+func TestAccGuacamoleConnectionVNCReverseConnect(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckGuacamoleConnectionVNCConfigReverseConnect(30000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleConnectionVNCExists("guacamole_connection_vnc.reverse"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.reverse", "parameters.0.reverse_connect", "true"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.reverse", "parameters.0.listen_timeout", "30000"),
+				),
+			},
+			{
+				Config: testAccCheckGuacamoleConnectionVNCConfigReverseConnect(60000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.reverse", "parameters.0.listen_timeout", "60000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGuacamoleConnectionVNCWakeOnLAN(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckGuacamoleConnectionVNCConfigWakeOnLAN(true, 30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleConnectionVNCExists("guacamole_connection_vnc.wol"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.wol", "parameters.0.wol_send_packet", "true"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.wol", "parameters.0.wol_mac_address", "00:11:22:33:44:55"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.wol", "parameters.0.wol_boot_wait_time", "30"),
+				),
+			},
+			{
+				Config: testAccCheckGuacamoleConnectionVNCConfigWakeOnLAN(false, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.wol", "parameters.0.wol_send_packet", "false"),
+					resource.TestCheckResourceAttr("guacamole_connection_vnc.wol", "parameters.0.wol_boot_wait_time", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceConnectionVNCCustomizeDiff(t *testing.T) {
+	cases := map[string]struct {
+		parameters map[string]interface{}
+		err        string
+	}{
+		"reverse connect": {
+			parameters: map[string]interface{}{
+				"reverse_connect": true,
+				"listen_timeout":  5000,
+			},
+		},
+		"listen timeout without reverse connect": {
+			parameters: map[string]interface{}{
+				"listen_timeout": 5000,
+			},
+			err: "requires reverse_connect",
+		},
+		"reverse connect with repeater": {
+			parameters: map[string]interface{}{
+				"reverse_connect":  true,
+				"destination_host": "repeater.example.com",
+				"wol_send_packet":  true,
+			},
+			err: "destination_host, wol_send_packet",
+		},
+	}
+
+	for name, c := range cases {
+		c.parameters["hostname"] = "0.0.0.0"
+		c.parameters["username"] = "user"
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":              "vnc",
+			"parent_identifier": "ROOT",
+			"parameters":        []interface{}{c.parameters},
+		})
+		_, err := guacamoleConnectionVNC().Diff(context.Background(), nil, config, nil)
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: expected error containing %q, got %v", name, c.err, err)
+		}
+	}
+}
+
+func TestConnectionVNCGuacdParameters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionVNC().Schema, map[string]interface{}{
+		"name": "vnc",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":        "0.0.0.0",
+				"username":        "kiosk",
+				"reverse_connect": true,
+				"listen_timeout":  5000,
+				"encodings":       []interface{}{"tight", "raw"},
+			},
+		},
+	})
+
+	_, parameters, diags := convertResourceDataToGuacConnection(d, "vnc")
+	if diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	expected := map[string]string{
+		"reverse-connect": "true",
+		"listen-timeout":  "5000",
+		"encodings":       "tight raw",
+	}
+	if !reflect.DeepEqual(parameters, expected) {
+		t.Fatalf("expected guacd parameters %v, got %v", expected, parameters)
+	}
+
+	read := schema.TestResourceDataRaw(t, guacamoleConnectionVNC().Schema, map[string]interface{}{})
+	connection := types.GuacConnection{Parameters: types.GuacConnectionParameters{Hostname: "0.0.0.0"}}
+	if diags := convertGuacConnectionToResourceData(read, &connection, parameters, "vnc"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if !reflect.DeepEqual(read.Get("display.0.encodings"), []interface{}{"tight", "raw"}) {
+		t.Fatalf("expected encodings to be read back, got %v", read.Get("display.0.encodings"))
+	}
+	if read.Get("network.0.listen_timeout").(int) != 5000 {
+		t.Fatalf("expected listen_timeout to be read back, got %v", read.Get("network.0.listen_timeout"))
+	}
+}
+
+func testAccCheckGuacamoleConnectionVNCConfigReverseConnect(listenTimeout int) string {
+	return fmt.Sprintf(`
+	resource "guacamole_connection_vnc" "reverse" {
+	  name              = "testProviderConnectionVNCReverse"
+	  parent_identifier = "ROOT"
+	  parameters {
+	    hostname        = "0.0.0.0"
+	    port            = 5500
+	    username        = "kiosk"
+	    reverse_connect = true
+	    listen_timeout  = %d
+	  }
+	}
+	`, listenTimeout)
+}
+
+func testAccCheckGuacamoleConnectionVNCConfigWakeOnLAN(sendPacket bool, bootWaitTime int) string {
+	return fmt.Sprintf(`
+	resource "guacamole_connection_vnc" "wol" {
+	  name              = "testProviderConnectionVNCWakeOnLAN"
+	  parent_identifier = "ROOT"
+	  parameters {
+	    hostname              = "hostname.example.com"
+	    username              = "user"
+	    wol_send_packet       = %t
+	    wol_mac_address       = "00:11:22:33:44:55"
+	    wol_broadcast_address = "255.255.255.255"
+	    wol_boot_wait_time    = %d
+	  }
+	}
+	`, sendPacket, bootWaitTime)
+}
+
 func testAccCheckGuacamoleConnectionVNCConfigBasic(connection string) string {
 	return fmt.Sprintf(`
 	resource "guacamole_connection_vnc" "new" %s
@@ -139,3 +325,84 @@ func testAccCheckGuacamoleConnectionVNCExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func TestGuacamoleConnectionVNCFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionVNC",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "vnc.example.com",
+				"port":     5901,
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "user",
+				"password": "password",
+			},
+		},
+		"display": []interface{}{
+			map[string]interface{}{
+				"encodings":      []interface{}{"tight", "zrle"},
+				"compress_level": 5,
+			},
+		},
+	}
+	state := testFakeApply(t, guacamoleConnectionVNC(), nil, config, client)
+
+	connection, ok := f.connections[state.ID]
+	if !ok {
+		t.Fatalf("connection %s was not created", state.ID)
+	}
+	expected := map[string]string{
+		"hostname":       "vnc.example.com",
+		"port":           "5901",
+		"username":       "user",
+		"password":       "password",
+		"encodings":      "tight zrle",
+		"compress-level": "5",
+	}
+	if connection.protocol != "vnc" || !reflect.DeepEqual(connection.parameters, expected) {
+		t.Errorf("expected vnc parameters %v, got %s parameters %v", expected, connection.protocol, connection.parameters)
+	}
+
+	config["network"] = []interface{}{
+		map[string]interface{}{
+			"hostname":        "vnc.example.com",
+			"reverse_connect": true,
+			"listen_timeout":  10000,
+		},
+	}
+	state = testFakeApply(t, guacamoleConnectionVNC(), state, config, client)
+
+	if connection.parameters["reverse-connect"] != "true" || connection.parameters["listen-timeout"] != "10000" {
+		t.Errorf("expected reverse connect parameters, got %v", connection.parameters)
+	}
+	if _, ok := connection.parameters["port"]; ok {
+		t.Errorf("expected port to be removed, got %v", connection.parameters)
+	}
+
+	ds := dataSourceConnectionVNC()
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"path": "fakeConnectionVNC",
+	})
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unable to read connection by path: %v", diags)
+	}
+	if d.Id() != state.ID || !d.Get("network.0.reverse_connect").(bool) {
+		t.Errorf("expected connection %s with reverse_connect, got %s", state.ID, d.Id())
+	}
+
+	testFakeDestroy(t, guacamoleConnectionVNC(), state, client)
+
+	if len(f.connections) != 0 {
+		t.Errorf("expected no connections to remain, got %d", len(f.connections))
+	}
+}
@@ -0,0 +1,178 @@
+package guacamole
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func guacamoleSelfPassword() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSelfPasswordCreate,
+		ReadContext:   resourceSelfPasswordRead,
+		UpdateContext: resourceSelfPasswordUpdate,
+		DeleteContext: resourceSelfPasswordDelete,
+		CustomizeDiff: resourceSelfPasswordCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Description: "Username of the authenticated user",
+				Computed:    true,
+			},
+			"old_password": {
+				Type:        schema.TypeString,
+				Description: "Current password of the authenticated user (defaults to the provider password)",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"new_password": {
+				Type:        schema.TypeString,
+				Description: "New password of the authenticated user (a random password is generated if not set)",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"password_length": {
+				Type:             schema.TypeInt,
+				Description:      "Length of the generated password",
+				Optional:         true,
+				Default:          defaultPasswordLength,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(8, 256)),
+			},
+			"password_charset": {
+				Type:             schema.TypeString,
+				Description:      "Characters the generated password is made up of",
+				Optional:         true,
+				Default:          defaultPasswordCharset,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(2, 256)),
+			},
+			"password_version": {
+				Type:        schema.TypeString,
+				Description: "Arbitrary value that changes the password again whenever it changes",
+				Optional:    true,
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Description: "Current password of the authenticated user",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func resourceSelfPasswordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChange("new_password") && !d.HasChange("password_version") {
+		return nil
+	}
+
+	if newPassword := d.Get("new_password").(string); newPassword != "" {
+		return d.SetNew("password", newPassword)
+	}
+	return d.SetNewComputed("password")
+}
+
+func resourceSelfPasswordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	username := client.session.Username
+	if username == "" {
+		self, err := client.ReadSelf()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		username = self.Username
+	}
+
+	oldPassword := d.Get("old_password").(string)
+	if oldPassword == "" {
+		oldPassword = client.password()
+	}
+
+	diags := changeSelfPassword(d, client, username, oldPassword)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(username)
+
+	return resourceSelfPasswordRead(ctx, d, m)
+}
+
+func resourceSelfPasswordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// Guacamole never returns passwords so the password in state is kept as is
+	d.Set("username", d.Id())
+
+	return diags
+}
+
+func resourceSelfPasswordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*guacamoleClient)
+
+	if d.HasChanges("new_password", "password_version") {
+		oldPassword, _ := d.GetChange("password")
+
+		diags := changeSelfPassword(d, client, d.Id(), oldPassword.(string))
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceSelfPasswordRead(ctx, d, m)
+}
+
+func resourceSelfPasswordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// The password can't be changed back so it is only removed from state
+	d.SetId("")
+
+	return diags
+}
+
+// changeSelfPassword changes the password of the authenticated user and re-authenticates the
+// client with the new password so the remaining operations of the run keep working
+func changeSelfPassword(d *schema.ResourceData, client *guacamoleClient, username string, oldPassword string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	newPassword := d.Get("new_password").(string)
+	if newPassword == "" {
+		var err error
+		newPassword, err = generatePassword(d.Get("password_length").(int), d.Get("password_charset").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := client.UpdateSelfPassword(username, oldPassword, newPassword)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Error changing password of guacamole user: %s", username),
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+
+	d.Set("password", newPassword)
+
+	// Token based sessions stay valid and can't be re-established with a password
+	if client.password() != "" {
+		err = client.reauthenticate(newPassword)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Error re-authenticating guacamole user: %s", username),
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,141 @@
+package guacamole
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	guac "github.com/techBeck03/guacamole-api-client"
+)
+
+func TestGuacamoleSelfPasswordFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+	r := guacamoleSelfPassword()
+
+	config := map[string]interface{}{
+		"password_length":  24,
+		"password_version": "1",
+	}
+	state := testFakeApply(t, r, nil, config, client)
+
+	first := state.Attributes["password"]
+	if len(first) != 24 {
+		t.Fatalf("expected a generated password of 24 characters, got %q", first)
+	}
+	testFakeCheckSelfPassword(t, f, client, first)
+
+	// rotating the password again re-authenticates with the new password
+	config["password_version"] = "2"
+	state = testFakeApply(t, r, state, config, client)
+
+	second := state.Attributes["password"]
+	if second == first || len(second) != 24 {
+		t.Fatalf("expected password_version to generate a new password, got %q after %q", second, first)
+	}
+	testFakeCheckSelfPassword(t, f, client, second)
+
+	config["new_password"] = "chosen-password"
+	state = testFakeApply(t, r, state, config, client)
+	if state.Attributes["password"] != "chosen-password" {
+		t.Fatalf("expected the configured password in state, got %q", state.Attributes["password"])
+	}
+	testFakeCheckSelfPassword(t, f, client, "chosen-password")
+
+	testFakeDestroy(t, r, state, client)
+	testFakeCheckSelfPassword(t, f, client, "chosen-password")
+}
+
+// testFakeCheckSelfPassword checks that the password of guacadmin was changed, that the client
+// authenticated again with it and that it holds a single session
+func testFakeCheckSelfPassword(t *testing.T, f *fakeGuacamole, client *guacamoleClient, password string) {
+	t.Helper()
+
+	f.mu.Lock()
+	stored := f.users[fakeGuacamoleUsername].password
+	sessions := len(f.sessions)
+	f.mu.Unlock()
+
+	if stored != password {
+		t.Fatalf("expected guacadmin password %q, got %q", password, stored)
+	}
+	if client.password() != password {
+		t.Fatalf("expected the client to authenticate with %q, got %q", password, client.password())
+	}
+	if sessions != 1 {
+		t.Fatalf("expected the previous session to be removed, got %d sessions", sessions)
+	}
+	if _, err := client.ReadSelf(); err != nil {
+		t.Fatalf("expected the client to keep working after re-authenticating: %s", err)
+	}
+}
+
+func TestReauthenticateConcurrentRequests(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+
+	done := make(chan struct{})
+	errs := make(chan error, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := client.ReadUser(fakeGuacamoleUsername); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	password := fakeGuacamolePassword
+	for i := 0; i < 5; i++ {
+		next := fmt.Sprintf("password-%d", i)
+		if err := client.UpdateSelfPassword(fakeGuacamoleUsername, password, next); err != nil {
+			t.Fatalf("unable to change password: %s", err)
+		}
+		if err := client.reauthenticate(next); err != nil {
+			t.Fatalf("unable to re-authenticate: %s", err)
+		}
+		password = next
+	}
+	close(done)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		t.Fatalf("expected requests running during re-authentication to succeed: %s", err)
+	default:
+	}
+}
+
+func TestGuacamoleClientHoldsSession(t *testing.T) {
+	// methods of the api client that don't make requests
+	requestless := map[string]bool{
+		"Connect":           true,
+		"CreateJSONRequest": true,
+	}
+
+	api := reflect.TypeOf(&guac.Client{})
+	wrapper := reflect.TypeOf(&guacamoleClient{})
+	for i := 0; i < api.NumMethod(); i++ {
+		name := api.Method(i).Name
+		if requestless[name] || strings.HasPrefix(name, "New") {
+			continue
+		}
+		method, _ := wrapper.MethodByName(name)
+		file, _ := runtime.FuncForPC(method.Func.Pointer()).FileLine(method.Func.Pointer())
+		// methods promoted from the embedded api client don't hold the session
+		if file == "<autogenerated>" {
+			t.Errorf("api client method %s is not wrapped to hold the session", name)
+		}
+	}
+}
@@ -2,17 +2,28 @@ package guacamole
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"math/big"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
+const (
+	totpKeySecretAttribute    = "guac-totp-key-secret"
+	totpKeyConfirmedAttribute = "guac-totp-key-confirmed"
+
+	defaultPasswordLength  = 24
+	defaultPasswordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+"
+)
+
 func guacamoleUser() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceUserCreate,
@@ -27,83 +38,119 @@ func guacamoleUser() *schema.Resource {
 				ForceNew:    true,
 			},
 			"password": {
-				Type:        schema.TypeString,
-				Description: "Password of guacamole user",
+				Type:          schema.TypeString,
+				Description:   "Password of guacamole user",
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"generate_password"},
+			},
+			"generate_password": {
+				Type:        schema.TypeBool,
+				Description: "Whether to generate a random password for the guacamole user",
 				Optional:    true,
+				Default:     false,
+			},
+			"password_length": {
+				Type:             schema.TypeInt,
+				Description:      "Length of the generated password",
+				Optional:         true,
+				Default:          defaultPasswordLength,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(8, 256)),
+			},
+			"password_charset": {
+				Type:             schema.TypeString,
+				Description:      "Characters the generated password is made up of",
+				Optional:         true,
+				Default:          defaultPasswordCharset,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringLenBetween(2, 256)),
+			},
+			"generated_password": {
+				Type:        schema.TypeString,
+				Description: "Password generated for the guacamole user",
+				Computed:    true,
 				Sensitive:   true,
 			},
+			"password_version": {
+				Type:        schema.TypeString,
+				Description: "Arbitrary value that generates a new password for the guacamole user whenever it changes, requires generate_password",
+				Optional:    true,
+			},
 			"last_active": {
 				Type:        schema.TypeString,
 				Description: "Epoch time string of last user activity",
 				Computed:    true,
 			},
-			"attributes": {
+			"attributes": attributesSchema("Attributes of guacamole user", map[string]*schema.Schema{
+				"organizational_role": {
+					Type:        schema.TypeString,
+					Description: "Organizational role of user",
+					Optional:    true,
+				},
+				"full_name": {
+					Type:        schema.TypeString,
+					Description: "Full name of user",
+					Optional:    true,
+				},
+				"email": {
+					Type:        schema.TypeString,
+					Description: "Email of user",
+					Optional:    true,
+				},
+				"expired": {
+					Type:        schema.TypeBool,
+					Description: "Whether the user is expired",
+					Optional:    true,
+					Computed:    false,
+				},
+				"timezone": {
+					Type:        schema.TypeString,
+					Description: "Timezone of user",
+					Optional:    true,
+				},
+				"access_window_start": {
+					Type:        schema.TypeString,
+					Description: "Access window start time for user",
+					Optional:    true,
+				},
+				"access_window_end": {
+					Type:        schema.TypeString,
+					Description: "Access window end time for user",
+					Optional:    true,
+				},
+				"disabled": {
+					Type:        schema.TypeBool,
+					Description: "Whether account is disabled",
+					Optional:    true,
+				},
+				"valid_from": {
+					Type:        schema.TypeString,
+					Description: "Start date for when user is valid",
+					Optional:    true,
+				},
+				"valid_until": {
+					Type:        schema.TypeString,
+					Description: "End date for when user is valid",
+					Optional:    true,
+				},
+			}),
+			"extra_attributes": extraAttributesSchema(),
+			"totp": {
 				Type:        schema.TypeList,
-				Description: "Attributes of guacamole user",
+				Description: "TOTP enrollment of guacamole user",
 				Optional:    true,
 				Computed:    true,
 				MaxItems:    1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"organizational_role": {
-							Type:        schema.TypeString,
-							Description: "Organizational role of user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"full_name": {
-							Type:        schema.TypeString,
-							Description: "Full name of user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"email": {
-							Type:        schema.TypeString,
-							Description: "Email of user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"expired": {
-							Type:        schema.TypeBool,
-							Description: "Whether the user is expired",
-							Optional:    true,
-							Computed:    false,
-						},
-						"timezone": {
-							Type:        schema.TypeString,
-							Description: "Timezone of user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"access_window_start": {
-							Type:        schema.TypeString,
-							Description: "Access window start time for user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"access_window_end": {
-							Type:        schema.TypeString,
-							Description: "Access window end time for user",
-							Optional:    true,
-							Computed:    true,
-						},
-						"disabled": {
+						"enrolled": {
 							Type:        schema.TypeBool,
-							Description: "Whether account is disabled",
-							Optional:    true,
+							Description: "Whether the user has completed TOTP enrollment",
 							Computed:    true,
 						},
-						"valid_from": {
+						"reset_trigger": {
 							Type:        schema.TypeString,
-							Description: "Start date for when user is valid",
+							Description: "Arbitrary value that resets the TOTP enrollment of the user whenever it changes from one non-empty value to another",
 							Optional:    true,
-							Computed:    true,
-						},
-						"valid_until": {
-							Type:        schema.TypeString,
-							Description: "End date for when user is valid",
-							Optional:    true,
-							Computed:    true,
 						},
 					},
 				},
@@ -141,15 +188,53 @@ func guacamoleUser() *schema.Resource {
 				},
 			},
 		},
-                Importer: &schema.ResourceImporter{
-                        StateContext: schema.ImportStatePassthroughContext,
-                },
+		Importer: &schema.ResourceImporter{
+			StateContext: importUserState,
+		},
+		CustomizeDiff: resourceUserCustomizeDiff,
 	}
 }
 
+func resourceUserCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	err := extraAttributesCustomizeDiff(attributeSchemaUser, typedUserAttributes)(ctx, d, m)
+	if err != nil {
+		return err
+	}
+
+	generate := d.Get("generate_password").(bool)
+
+	// Rotating a configured password would only apply the same password again
+	if d.NewValueKnown("generate_password") && !generate && d.Get("password_version").(string) != "" {
+		return fmt.Errorf("password_version requires generate_password to be set, change password to rotate a configured password")
+	}
+
+	// A new password is generated when generation is enabled or the password version changes
+	if generate && (d.HasChange("generate_password") || d.HasChange("password_version")) {
+		return d.SetNewComputed("generated_password")
+	}
+
+	if !generate && d.Get("generated_password").(string) != "" {
+		return d.SetNew("generated_password", "")
+	}
+
+	// Changing reset_trigger resets the TOTP enrollment, which needs the TOTP extension
+	client, _ := m.(*guacamoleClient)
+	if previous, trigger := d.GetChange("totp.0.reset_trigger"); client != nil && previous.(string) != "" && trigger.(string) != "" && previous != trigger {
+		supported, err := userTOTPSupported(client)
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return fmt.Errorf("unable to reset TOTP enrollment of guacamole user %s: the guacamole server has no TOTP extension installed", d.Get("username").(string))
+		}
+	}
+
+	return nil
+}
+
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	check := validateUser(d)
 	if check.HasError() {
@@ -162,7 +247,21 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	err = client.CreateUser(&user)
+	if d.Get("generate_password").(bool) {
+		user.Password, err = generatePassword(d.Get("password_length").(int), d.Get("password_charset").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("generated_password", user.Password)
+	}
+
+	attributes, err := buildObjectAttributes(d, client, "", user.Attributes)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.WriteObject(http.MethodPost, "users", &user, attributes, &user)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -259,7 +358,7 @@ Cleanup:
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -283,6 +382,18 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		return diag.FromErr(err)
 	}
 
+	err = setExtraAttributes(d, client, userObjectPath(userID), typedUserAttributes, false)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = setUserTOTP(d, client, userID)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Read group membership
 	groups, err := client.GetUserGroupMembership(userID)
 
@@ -321,9 +432,11 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
+
+	rotate := d.Get("generate_password").(bool) && d.HasChanges("generate_password", "password_version")
 
-	if d.HasChanges("username", "password", "last_active", "attributes") {
+	if d.HasChanges("username", "password", "generate_password", "last_active", "attributes", "extra_attributes") || rotate {
 		check := validateUser(d)
 		if check.HasError() {
 			return check
@@ -333,11 +446,42 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		err = client.UpdateUser(&user)
 
+		if d.Get("generate_password").(bool) {
+			if rotate {
+				user.Password, err = generatePassword(d.Get("password_length").(int), d.Get("password_charset").(string))
+				if err != nil {
+					return diag.FromErr(err)
+				}
+			} else {
+				user.Password = d.Get("generated_password").(string)
+			}
+		}
+
+		attributes, err := buildObjectAttributes(d, client, userObjectPath(user.Username), user.Attributes)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+
+		err = client.WriteObject(http.MethodPut, userObjectPath(user.Username), &user, attributes, nil)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if d.Get("generate_password").(bool) {
+			d.Set("generated_password", user.Password)
+		} else {
+			d.Set("generated_password", "")
+		}
+	}
+
+	// Setting the trigger for the first time records a baseline rather than resetting enrollment
+	if previous, trigger := d.GetChange("totp.0.reset_trigger"); previous.(string) != "" && trigger.(string) != "" && previous != trigger {
+		check := resetUserTOTP(client, d.Id())
+		if check.HasError() {
+			return check
+		}
 	}
 
 	if d.HasChange("group_membership") {
@@ -491,7 +635,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -537,7 +681,6 @@ func convertResourceDataToGuacUser(d *schema.ResourceData) (types.GuacUser, erro
 
 func convertGuacUserToResourceData(d *schema.ResourceData, user *types.GuacUser) error {
 	d.Set("username", user.Username)
-	d.Set("password", user.Password)
 	d.Set("last_active", strconv.Itoa(user.LastActive))
 
 	attributes := map[string]interface{}{
@@ -562,7 +705,7 @@ func convertGuacUserToResourceData(d *schema.ResourceData, user *types.GuacUser)
 	return nil
 }
 
-func validateGroups(client *guac.Client, groups []string) diag.Diagnostics {
+func validateGroups(client *guacamoleClient, groups []string) diag.Diagnostics {
 	var diags diag.Diagnostics
 	var invalidUserGroups []string
 
@@ -630,3 +773,105 @@ func validateUser(d *schema.ResourceData) diag.Diagnostics {
 	}
 	return diags
 }
+
+// generatePassword returns a random password of the given length drawn from charset
+func generatePassword(length int, charset string) (string, error) {
+	chars := []rune(charset)
+	max := big.NewInt(int64(len(chars)))
+
+	password := make([]rune, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = chars[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// setUserTOTP reads the TOTP enrollment of a user into the totp block
+func setUserTOTP(d *schema.ResourceData, client *guacamoleClient, username string) error {
+	attributes, err := client.ReadObjectAttributes(userObjectPath(username))
+	if err != nil {
+		return err
+	}
+
+	enrolled := false
+	if confirmed, ok := attributes[totpKeyConfirmedAttribute]; ok && confirmed != nil {
+		enrolled = stringToBool(*confirmed)
+	}
+
+	totp := []map[string]interface{}{
+		{
+			"enrolled":      enrolled,
+			"reset_trigger": d.Get("totp.0.reset_trigger").(string),
+		},
+	}
+	return d.Set("totp", totp)
+}
+
+// userTOTPSupported checks the user attribute schema for the attributes of the TOTP extension
+func userTOTPSupported(client *guacamoleClient) (bool, error) {
+	forms, err := client.GetAttributeSchema(attributeSchemaUser)
+	if err != nil {
+		return false, err
+	}
+	for _, form := range forms {
+		for _, field := range form.Fields {
+			if field.Name == totpKeyConfirmedAttribute {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// resetUserTOTP clears the TOTP key of a user so that the user enrolls again on next login
+func resetUserTOTP(client *guacamoleClient, username string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	supported, err := userTOTPSupported(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !supported {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to reset TOTP enrollment",
+			Detail:   fmt.Sprintf("Unable to reset TOTP enrollment of guacamole user %s: the guacamole server has no TOTP extension installed", username),
+		})
+		return diags
+	}
+
+	user, err := client.ReadUser(username)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current, err := client.ReadObjectAttributes(userObjectPath(username))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	attributes := make(map[string]interface{})
+	for k, v := range current {
+		if v == nil {
+			attributes[k] = nil
+		} else {
+			attributes[k] = *v
+		}
+	}
+	attributes[totpKeySecretAttribute] = nil
+	attributes[totpKeyConfirmedAttribute] = nil
+
+	err = client.WriteObject(http.MethodPut, userObjectPath(username), &user, attributes, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Error resetting TOTP enrollment of guacamole user: %s", username),
+			Detail:   err.Error(),
+		})
+	}
+
+	return diags
+}
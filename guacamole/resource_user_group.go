@@ -3,10 +3,10 @@ package guacamole
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	guac "github.com/techBeck03/guacamole-api-client"
 	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
@@ -16,6 +16,7 @@ func guacamoleUserGroup() *schema.Resource {
 		ReadContext:   resourceUserGroupRead,
 		UpdateContext: resourceUserGroupUpdate,
 		DeleteContext: resourceUserGroupDelete,
+		CustomizeDiff: extraAttributesCustomizeDiff(attributeSchemaUserGroup, typedUserGroupAttributes),
 		Schema: map[string]*schema.Schema{
 			"identifier": {
 				Type:        schema.TypeString,
@@ -23,23 +24,14 @@ func guacamoleUserGroup() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 			},
-			"attributes": {
-				Type:        schema.TypeList,
-				Description: "Attributes of guacamole user group",
-				Optional:    true,
-				Computed:    true,
-				MaxItems:    1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"disabled": {
-							Type:        schema.TypeBool,
-							Description: "Whether group is disabled",
-							Optional:    true,
-							Computed:    true,
-						},
-					},
+			"extra_attributes": extraAttributesSchema(),
+			"attributes": attributesSchema("Attributes of guacamole user group", map[string]*schema.Schema{
+				"disabled": {
+					Type:        schema.TypeBool,
+					Description: "Whether group is disabled",
+					Optional:    true,
 				},
-			},
+			}),
 			"group_membership": {
 				Type:        schema.TypeSet,
 				Description: "Groups this user group is a member of",
@@ -73,14 +65,14 @@ func guacamoleUserGroup() *schema.Resource {
 				},
 			},
 		},
-                Importer: &schema.ResourceImporter{
-                        StateContext: schema.ImportStatePassthroughContext,
-                },
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 	}
 }
 
 func resourceUserGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -91,7 +83,13 @@ func resourceUserGroupCreate(ctx context.Context, d *schema.ResourceData, m inte
 		return diag.FromErr(err)
 	}
 
-	err = client.CreateUserGroup(&group)
+	attributes, err := buildObjectAttributes(d, client, "", group.Attributes)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.WriteObject(http.MethodPost, "userGroups", &group, attributes, &group)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -190,7 +188,7 @@ Cleanup:
 }
 
 func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -214,6 +212,12 @@ func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.FromErr(err)
 	}
 
+	err = setExtraAttributes(d, client, userGroupObjectPath(identifier), typedUserGroupAttributes, false)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Read group membership
 	groups, err := client.GetUserGroupMemberGroups(identifier)
 
@@ -252,14 +256,20 @@ func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceUserGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
-	if d.HasChanges("username", "attributes") {
+	if d.HasChanges("username", "attributes", "extra_attributes") {
 		group, err := convertResourceDataToGuacUserGroup(d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		err = client.UpdateUserGroup(&group)
+
+		attributes, err := buildObjectAttributes(d, client, userGroupObjectPath(group.Identifier), group.Attributes)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.WriteObject(http.MethodPut, userGroupObjectPath(group.Identifier), &group, attributes, nil)
 
 		if err != nil {
 			return diag.FromErr(err)
@@ -417,7 +427,7 @@ func resourceUserGroupUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceUserGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*guac.Client)
+	client := m.(*guacamoleClient)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
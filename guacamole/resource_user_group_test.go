@@ -2,11 +2,11 @@ package guacamole
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	guac "github.com/techBeck03/guacamole-api-client"
 	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
@@ -16,7 +16,7 @@ var testProviderUserGroup = map[string]interface{}{
 }
 
 func TestAccGuacamoleUserGroupBasic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -33,7 +33,7 @@ func TestAccGuacamoleUserGroupBasic(t *testing.T) {
 }
 
 func testAccCheckGuacamoleUserGroupDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*guac.Client)
+	c := testAccProvider.Meta().(*guacamoleClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "guacamole_user_group" {
@@ -72,3 +72,75 @@ func testAccCheckGuacamoleUserGroupExists(resourceName string) resource.TestChec
 		return nil
 	}
 }
+
+func TestGuacamoleUserGroupFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	connectionState := testFakeApply(t, guacamoleConnectionSSH(), nil, map[string]interface{}{
+		"name": "fakeConnectionSSH",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 1,
+			},
+		},
+		"network": []interface{}{
+			map[string]interface{}{
+				"hostname": "hostname.example.com",
+			},
+		},
+		"authentication": []interface{}{
+			map[string]interface{}{
+				"username": "user",
+			},
+		},
+	}, client)
+
+	config := map[string]interface{}{
+		"identifier": "fakeGroup",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"disabled": true,
+			},
+		},
+		"system_permissions": []interface{}{"CREATE_CONNECTION", "CREATE_USER"},
+		"connections":        []interface{}{connectionState.ID},
+	}
+	state := testFakeApply(t, guacamoleUserGroup(), nil, config, client)
+
+	group, ok := f.userGroups["fakeGroup"]
+	if !ok {
+		t.Fatalf("user group fakeGroup was not created")
+	}
+	if disabled := group.attributes["disabled"]; disabled == nil || *disabled != "true" {
+		t.Errorf("expected disabled attribute true, got %v", disabled)
+	}
+	if !reflect.DeepEqual(group.permissions.view().ConnectionPermissions, map[string][]string{connectionState.ID: {"READ"}}) {
+		t.Errorf("expected READ permission on connection %s, got %v", connectionState.ID, group.permissions.view().ConnectionPermissions)
+	}
+
+	config["system_permissions"] = []interface{}{"CREATE_USER"}
+	config["connections"] = []interface{}{}
+	state = testFakeApply(t, guacamoleUserGroup(), state, config, client)
+
+	if !reflect.DeepEqual(sortedKeys(group.permissions.system), []string{"CREATE_USER"}) {
+		t.Errorf("expected system permission CREATE_USER, got %v", sortedKeys(group.permissions.system))
+	}
+	if len(group.permissions.view().ConnectionPermissions) != 0 {
+		t.Errorf("expected connection permissions to be revoked, got %v", group.permissions.view().ConnectionPermissions)
+	}
+
+	// removing the attributes block enables the group again
+	delete(config, "attributes")
+	state = testFakeApply(t, guacamoleUserGroup(), state, config, client)
+
+	if disabled, ok := group.attributes["disabled"]; ok {
+		t.Errorf("expected disabled attribute to be unset, got %s", *disabled)
+	}
+
+	testFakeDestroy(t, guacamoleUserGroup(), state, client)
+	testFakeDestroy(t, guacamoleConnectionSSH(), connectionState, client)
+
+	if len(f.userGroups) != 0 {
+		t.Errorf("expected no user groups to remain, got %d", len(f.userGroups))
+	}
+}
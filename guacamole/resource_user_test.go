@@ -1,12 +1,13 @@
 package guacamole
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	guac "github.com/techBeck03/guacamole-api-client"
 	types "github.com/techBeck03/guacamole-api-client/types"
 )
 
@@ -29,7 +30,7 @@ func TestAccGuacamoleUserBasic(t *testing.T) {
 		"group_membership":   []string{testProviderUserGroup["identifier"].(string)},
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckGuacamoleUserDestroy,
@@ -59,8 +60,34 @@ func TestAccGuacamoleUserBasic(t *testing.T) {
 	})
 }
 
+func TestAccGuacamoleUserGeneratedPassword(t *testing.T) {
+	var generatedPassword string
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckGuacamoleUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckGuacamoleUserConfigGeneratedPassword("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleUserExists("guacamole_user.generated"),
+					testAccCheckGuacamoleUserGeneratedPassword("guacamole_user.generated", &generatedPassword, false),
+				),
+			},
+			{
+				Config: testAccCheckGuacamoleUserConfigGeneratedPassword("2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGuacamoleUserExists("guacamole_user.generated"),
+					testAccCheckGuacamoleUserGeneratedPassword("guacamole_user.generated", &generatedPassword, true),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckGuacamoleUserDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*guac.Client)
+	c := testAccProvider.Meta().(*guacamoleClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "guacamole_user" {
@@ -103,3 +130,230 @@ func testAccCheckGuacamoleUserExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func testAccCheckGuacamoleUserConfigGeneratedPassword(version string) string {
+	return fmt.Sprintf(`
+	resource "guacamole_user" "generated" {
+		username          = "testProviderGeneratedUser"
+		generate_password = true
+		password_length   = 32
+		password_version  = "%s"
+	}
+	`, version)
+}
+
+// testAccCheckGuacamoleUserGeneratedPassword checks the generated password and whether it changed since the previous step
+func testAccCheckGuacamoleUserGeneratedPassword(n string, previous *string, rotated bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		password := rs.Primary.Attributes["generated_password"]
+		if len(password) != 32 {
+			return fmt.Errorf("Expected generated password of length 32, got %d", len(password))
+		}
+		if rotated && password == *previous {
+			return fmt.Errorf("Expected generated password to be rotated")
+		}
+
+		*previous = password
+		return nil
+	}
+}
+
+func TestGuacamoleUserFakeLifecycle(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	groupState := testFakeApply(t, guacamoleUserGroup(), nil, map[string]interface{}{
+		"identifier": "fakeGroup",
+	}, client)
+
+	config := map[string]interface{}{
+		"username": "fakeUser",
+		"password": "fakePassword",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"full_name": "Fake User",
+				"email":     "fake@example.com",
+				"timezone":  "America/Chicago",
+				"disabled":  true,
+			},
+		},
+		"system_permissions": []interface{}{"CREATE_USER"},
+		"group_membership":   []interface{}{"fakeGroup"},
+	}
+	state := testFakeApply(t, guacamoleUser(), nil, config, client)
+
+	user, ok := f.users["fakeUser"]
+	if !ok {
+		t.Fatalf("user fakeUser was not created")
+	}
+	if user.password != "fakePassword" {
+		t.Errorf("expected password fakePassword, got %s", user.password)
+	}
+	if name := user.attributes["guac-full-name"]; name == nil || *name != "Fake User" {
+		t.Errorf("expected full name Fake User, got %v", name)
+	}
+	if !user.permissions.system["CREATE_USER"] || len(user.permissions.system) != 1 {
+		t.Errorf("expected system permission CREATE_USER, got %v", user.permissions.system)
+	}
+	if !f.userGroups["fakeGroup"].memberUsers["fakeUser"] {
+		t.Errorf("expected fakeUser to be a member of fakeGroup")
+	}
+
+	config["attributes"] = []interface{}{
+		map[string]interface{}{
+			"full_name": "Renamed User",
+		},
+	}
+	config["group_membership"] = []interface{}{}
+	state = testFakeApply(t, guacamoleUser(), state, config, client)
+
+	if name := user.attributes["guac-full-name"]; name == nil || *name != "Renamed User" {
+		t.Errorf("expected full name Renamed User, got %v", name)
+	}
+	if _, ok := user.attributes["disabled"]; ok {
+		t.Errorf("expected disabled attribute to be unset")
+	}
+	if f.userGroups["fakeGroup"].memberUsers["fakeUser"] {
+		t.Errorf("expected fakeUser to be removed from fakeGroup")
+	}
+
+	// removing the attributes block unsets the attributes
+	delete(config, "attributes")
+	state = testFakeApply(t, guacamoleUser(), state, config, client)
+
+	if name, ok := user.attributes["guac-full-name"]; ok {
+		t.Errorf("expected full name to be unset, got %s", *name)
+	}
+
+	testFakeDestroy(t, guacamoleUser(), state, client)
+	testFakeDestroy(t, guacamoleUserGroup(), groupState, client)
+
+	if len(f.users) != 1 || len(f.userGroups) != 0 {
+		t.Errorf("expected only guacadmin to remain, got %d users and %d user groups", len(f.users), len(f.userGroups))
+	}
+}
+
+func TestGuacamoleUserFakePasswordVersion(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	_, err := guacamoleUser().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"username":         "fakeUser",
+		"password":         "fakePassword",
+		"password_version": "1",
+	}), client)
+	if err == nil || !strings.Contains(err.Error(), "password_version requires generate_password") {
+		t.Fatalf("expected password_version without generate_password to be rejected, got %v", err)
+	}
+
+	config := map[string]interface{}{
+		"username":          "fakeUser",
+		"generate_password": true,
+		"password_length":   16,
+		"password_version":  "1",
+	}
+	state := testFakeApply(t, guacamoleUser(), nil, config, client)
+
+	first := state.Attributes["generated_password"]
+	if len(first) != 16 || f.users["fakeUser"].password != first {
+		t.Fatalf("expected a generated password of 16 characters to be set, got %q and %q", first, f.users["fakeUser"].password)
+	}
+
+	config["password_version"] = "2"
+	state = testFakeApply(t, guacamoleUser(), state, config, client)
+
+	second := state.Attributes["generated_password"]
+	if second == first || f.users["fakeUser"].password != second {
+		t.Fatalf("expected password_version to set a new generated password, got %q after %q", second, first)
+	}
+
+	testFakeDestroy(t, guacamoleUser(), state, client)
+}
+
+func TestGuacamoleUserFakeTOTP(t *testing.T) {
+	f, client := newTestFakeGuacamole(t)
+
+	config := map[string]interface{}{
+		"username": "fakeUser",
+		"password": "fakePassword",
+		"totp": []interface{}{
+			map[string]interface{}{
+				"reset_trigger": "1",
+			},
+		},
+	}
+	// setting the trigger for the first time is a baseline, even without the TOTP extension
+	state := testFakeApply(t, guacamoleUser(), nil, config, client)
+	if state.Attributes["totp.0.enrolled"] != "false" {
+		t.Fatalf("expected fakeUser not to be enrolled, got %q", state.Attributes["totp.0.enrolled"])
+	}
+
+	config["totp"] = []interface{}{
+		map[string]interface{}{
+			"reset_trigger": "2",
+		},
+	}
+	_, err := guacamoleUser().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), client)
+	if err == nil || !strings.Contains(err.Error(), "no TOTP extension installed") {
+		t.Fatalf("expected planning a TOTP reset without the extension to fail, got %v", err)
+	}
+
+	testFakeDestroy(t, guacamoleUser(), state, client)
+
+	// the client caches attribute schemas, so the extension is installed on a fresh server
+	f, client = newTestFakeGuacamole(t)
+	f.installTOTP()
+
+	delete(config, "totp")
+	state = testFakeApply(t, guacamoleUser(), nil, config, client)
+
+	secret, confirmed := "JBSWY3DPEHPK3PXP", "true"
+	f.mu.Lock()
+	f.users["fakeUser"].attributes[totpKeySecretAttribute] = &secret
+	f.users["fakeUser"].attributes[totpKeyConfirmedAttribute] = &confirmed
+	f.mu.Unlock()
+
+	// adding the trigger to an enrolled user keeps the enrollment
+	config["totp"] = []interface{}{
+		map[string]interface{}{
+			"reset_trigger": "1",
+		},
+	}
+	state = testFakeApply(t, guacamoleUser(), state, config, client)
+	if f.users["fakeUser"].attributes[totpKeyConfirmedAttribute] == nil {
+		t.Fatalf("expected setting reset_trigger for the first time not to reset the TOTP key")
+	}
+
+	state, diags := guacamoleUser().RefreshWithoutUpgrade(context.Background(), state, client)
+	if diags.HasError() {
+		t.Fatalf("unable to refresh: %v", diags)
+	}
+	if state.Attributes["totp.0.enrolled"] != "true" {
+		t.Fatalf("expected fakeUser to be enrolled, got %q", state.Attributes["totp.0.enrolled"])
+	}
+	testFakeCheckNoChanges(t, guacamoleUser(), state, config, client)
+
+	config["totp"] = []interface{}{
+		map[string]interface{}{
+			"reset_trigger": "2",
+		},
+	}
+	state = testFakeApply(t, guacamoleUser(), state, config, client)
+
+	if state.Attributes["totp.0.enrolled"] != "false" {
+		t.Errorf("expected the reset to clear the enrollment in state, got %q", state.Attributes["totp.0.enrolled"])
+	}
+	f.mu.Lock()
+	_, hasSecret := f.users["fakeUser"].attributes[totpKeySecretAttribute]
+	_, hasConfirmed := f.users["fakeUser"].attributes[totpKeyConfirmedAttribute]
+	f.mu.Unlock()
+	if hasSecret || hasConfirmed {
+		t.Errorf("expected the reset to clear the TOTP key of fakeUser")
+	}
+
+	testFakeDestroy(t, guacamoleUser(), state, client)
+}
@@ -0,0 +1,165 @@
+package guacamole
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+const (
+	secretStateModePlaintext = "plaintext"
+	secretStateModeHash      = "hash"
+
+	secretHashPrefix = "sha256:"
+	secretSaltLength = 16
+)
+
+// secretStateModeSchema returns the schema of the argument controlling how connection secrets are stored in state
+func secretStateModeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Description:      "How secret connection parameters are stored in state (plaintext or hash)",
+		Optional:         true,
+		Default:          secretStateModePlaintext,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{secretStateModePlaintext, secretStateModeHash}, false)),
+	}
+}
+
+// connectionSecretFields maps the fields of the sensitive registry parameters to their values in
+// the connection parameters
+func connectionSecretFields(parameters *types.GuacConnectionParameters) map[string]*string {
+	fields := make(map[string]*string)
+	values := reflect.ValueOf(parameters).Elem()
+	for _, p := range connectionParameters {
+		if i, ok := guacConnectionParameterFields[p.name]; ok && p.sensitive {
+			fields[p.field] = values.Field(i).Addr().Interface().(*string)
+		}
+	}
+	return fields
+}
+
+// hashSecret returns the salted sha256 digest of a secret in the form sha256:<salt>:<digest>
+func hashSecret(secret string, salt string) string {
+	sum := sha256.Sum256([]byte(salt + secret))
+	return fmt.Sprintf("%s%s:%s", secretHashPrefix, salt, hex.EncodeToString(sum[:]))
+}
+
+// secretHashSalt returns the salt of a hashed secret and whether the value is a hashed secret
+func secretHashSalt(value string) (string, bool) {
+	if !strings.HasPrefix(value, secretHashPrefix) {
+		return "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(value, secretHashPrefix), ":")
+	if len(parts) != 2 || len(parts[1]) != sha256.Size*2 {
+		return "", false
+	}
+	return parts[0], true
+}
+
+func newSecretSalt() (string, error) {
+	salt := make([]byte, secretSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt), nil
+}
+
+// suppressSecretHashDiff suppresses the diff between a hashed secret in state and the
+// matching plaintext secret in configuration
+func suppressSecretHashDiff(k, old, new string, d *schema.ResourceData) bool {
+	salt, ok := secretHashSalt(old)
+	if !ok || new == "" {
+		return false
+	}
+	return hashSecret(new, salt) == old
+}
+
+// hashConnectionSecrets replaces the secret parameters read from guacamole with salted
+// digests when the resource stores secrets as hashes.  The salt of the digest already in
+// state is reused so that unchanged secrets produce the same digest and changed secrets
+// show up as drift.
+func hashConnectionSecrets(d *schema.ResourceData, parameters map[string]interface{}, sections []parameterSection) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// data sources share the conversion functions but have no secret_state_mode argument
+	mode, _ := d.Get("secret_state_mode").(string)
+	if mode != secretStateModeHash {
+		return diags
+	}
+
+	previousParameters := mergeParameterSections(d, sections)[0].(map[string]interface{})
+
+	for k := range connectionSecretFields(&types.GuacConnectionParameters{}) {
+		value, ok := parameters[k].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if _, hashed := secretHashSalt(value); hashed {
+			continue
+		}
+
+		previous, _ := previousParameters[k].(string)
+		salt, ok := secretHashSalt(previous)
+		if !ok {
+			var err error
+			salt, err = newSecretSalt()
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		parameters[k] = hashSecret(value, salt)
+	}
+
+	return diags
+}
+
+// resolveConnectionSecrets replaces hashed secrets about to be sent to guacamole with their
+// plaintext.  A hashed secret is only planned when the configured secret matches the digest
+// in state or the secret is not configured, so the plaintext is taken from the configuration
+// or, failing that, from the connection currently stored in guacamole.
+func resolveConnectionSecrets(d *schema.ResourceData, client *guacamoleClient, parameters *types.GuacConnectionParameters, sections []parameterSection) error {
+	var current *types.GuacConnectionParameters
+
+	config := d.GetRawConfig()
+	for k, field := range connectionSecretFields(parameters) {
+		if _, hashed := secretHashSalt(*field); !hashed {
+			continue
+		}
+
+		if plaintext := configuredConnectionParameter(config, sections, k); plaintext != "" {
+			*field = plaintext
+			continue
+		}
+
+		if current == nil {
+			connection, err := client.ReadConnection(d.Id())
+			if err != nil {
+				return err
+			}
+			current = &connection.Parameters
+		}
+		*field = *connectionSecretFields(current)[k]
+	}
+
+	return nil
+}
+
+// configuredConnectionParameter returns a string parameter from the raw resource configuration,
+// looking it up in its section block before the deprecated parameters block
+func configuredConnectionParameter(config cty.Value, sections []parameterSection, k string) string {
+	value, ok := configuredParameter(config, sections, k)
+	if !ok {
+		return ""
+	}
+	return value.AsString()
+}
@@ -0,0 +1,142 @@
+package guacamole
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	types "github.com/techBeck03/guacamole-api-client/types"
+)
+
+func testSecretConnectionSSH(password string) types.GuacConnection {
+	return types.GuacConnection{
+		Name:             "secrets",
+		Identifier:       "1",
+		ParentIdentifier: "ROOT",
+		Protocol:         "ssh",
+		Parameters: types.GuacConnectionParameters{
+			Hostname:   "testing.example.com",
+			Username:   "user",
+			Password:   password,
+			PrivateKey: "-----BEGIN KEY-----",
+		},
+	}
+}
+
+func TestConvertGuacConnectionSSHToResourceDataHashesSecrets(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionSSH().Schema, map[string]interface{}{
+		"name":              "secrets",
+		"secret_state_mode": secretStateModeHash,
+	})
+
+	connection := testSecretConnectionSSH("hunter2")
+	if diags := convertGuacConnectionToResourceData(d, &connection, nil, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+
+	password := d.Get("authentication.0.password").(string)
+	salt, ok := secretHashSalt(password)
+	if !ok {
+		t.Fatalf("expected hashed password in state, got %q", password)
+	}
+	if password != hashSecret("hunter2", salt) {
+		t.Fatalf("stored hash %q does not match password", password)
+	}
+	if _, ok := secretHashSalt(d.Get("authentication.0.private_key").(string)); !ok {
+		t.Fatalf("expected hashed private key in state")
+	}
+	if _, ok := secretHashSalt(d.Get("authentication.0.passphrase").(string)); ok {
+		t.Fatalf("expected empty passphrase to stay empty")
+	}
+	if d.Get("network.0.hostname").(string) != "testing.example.com" {
+		t.Fatalf("expected non secret parameters to be stored as is")
+	}
+
+	// Reading the unchanged secret again keeps the salt and digest
+	if diags := convertGuacConnectionToResourceData(d, &connection, nil, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if d.Get("authentication.0.password").(string) != password {
+		t.Fatalf("expected unchanged password to produce the same hash")
+	}
+	if !suppressSecretHashDiff("authentication.0.password", password, "hunter2", d) {
+		t.Fatalf("expected diff against matching configured password to be suppressed")
+	}
+
+	// A password changed outside of terraform is detected as drift
+	changed := testSecretConnectionSSH("changed")
+	if diags := convertGuacConnectionToResourceData(d, &changed, nil, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	drifted := d.Get("authentication.0.password").(string)
+	if drifted == password {
+		t.Fatalf("expected changed password to produce a different hash")
+	}
+	if suppressSecretHashDiff("authentication.0.password", drifted, "hunter2", d) {
+		t.Fatalf("expected diff against stale configured password to be shown")
+	}
+}
+
+func TestConvertGuacConnectionSSHToResourceDataPlaintextSecrets(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionSSH().Schema, map[string]interface{}{
+		"name": "secrets",
+	})
+
+	connection := testSecretConnectionSSH("hunter2")
+	if diags := convertGuacConnectionToResourceData(d, &connection, nil, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if d.Get("authentication.0.password").(string) != "hunter2" {
+		t.Fatalf("expected plaintext password in state")
+	}
+}
+
+func TestConvertGuacConnectionSSHDataSourceIgnoresSecretStateMode(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceConnectionSSH().Schema, map[string]interface{}{})
+
+	connection := testSecretConnectionSSH("hunter2")
+	if diags := convertGuacConnectionToResourceData(d, &connection, nil, "ssh"); diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if d.Get("authentication.0.private_key").(string) != "-----BEGIN KEY-----" {
+		t.Fatalf("expected data source to keep secrets as read")
+	}
+}
+
+func TestConfiguredConnectionParameter(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("secrets"),
+		"parameters": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"hostname":   cty.StringVal("testing.example.com"),
+				"password":   cty.StringVal("hunter2"),
+				"passphrase": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+
+	if v := configuredConnectionParameter(config, connectionParameterSections("ssh"), "password"); v != "hunter2" {
+		t.Fatalf("expected configured password, got %q", v)
+	}
+	if v := configuredConnectionParameter(config, connectionParameterSections("ssh"), "passphrase"); v != "" {
+		t.Fatalf("expected null passphrase to be empty, got %q", v)
+	}
+	if v := configuredConnectionParameter(config, connectionParameterSections("ssh"), "client_key"); v != "" {
+		t.Fatalf("expected missing parameter to be empty, got %q", v)
+	}
+
+	nested := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("secrets"),
+		"authentication": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"password": cty.StringVal("hunter3"),
+			}),
+		}),
+	})
+	if v := configuredConnectionParameter(nested, connectionParameterSections("ssh"), "password"); v != "hunter3" {
+		t.Fatalf("expected password configured in its section, got %q", v)
+	}
+	if v := configuredConnectionParameter(cty.NullVal(config.Type()), connectionParameterSections("ssh"), "password"); v != "" {
+		t.Fatalf("expected null configuration to be empty, got %q", v)
+	}
+}
@@ -0,0 +1,13 @@
+package guacamole
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+)
+
+// Serve serves the provider to terraform, then removes the sessions it opened.  Serving returns
+// once terraform asks the provider to shut down, which it does when it no longer needs it
+func Serve(opts *plugin.ServeOpts) {
+	plugin.Serve(opts)
+
+	CloseSessions()
+}
@@ -0,0 +1,77 @@
+package guacamole
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	guac "github.com/techBeck03/guacamole-api-client"
+)
+
+func TestServeClosesSessionsOnShutdown(t *testing.T) {
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+
+	_, err := connectGuacamole(guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	}, "")
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reattach := make(chan *goplugin.ReattachConfig, 1)
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		Serve(&plugin.ServeOpts{
+			ProviderFunc:        Provider,
+			Logger:              hclog.NewNullLogger(),
+			NoLogOutputOverride: true,
+			TestConfig: &goplugin.ServeTestConfig{
+				Context:          ctx,
+				ReattachConfigCh: reattach,
+				CloseCh:          make(chan struct{}, 1),
+			},
+		})
+	}()
+
+	var config *goplugin.ReattachConfig
+	select {
+	case config = <-reattach:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("provider didn't start serving")
+	}
+
+	// closing the client sends the Shutdown request terraform sends once it is done with the
+	// provider
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  plugin.Handshake,
+		Reattach:         config,
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Logger:           hclog.NewNullLogger(),
+	})
+	rpc, err := client.Client()
+	if err != nil {
+		t.Fatalf("unable to connect to the provider: %s", err)
+	}
+	rpc.Close()
+
+	select {
+	case <-served:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected serving to return once the provider is shut down")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sessions) != 0 {
+		t.Fatalf("expected the sessions of the provider to be removed, got %v", f.sessions)
+	}
+}
@@ -0,0 +1,195 @@
+package guacamole
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	guac "github.com/techBeck03/guacamole-api-client"
+)
+
+// cachedSession is the content of a session cache file
+type cachedSession struct {
+	URL        string `json:"url"`
+	Username   string `json:"username"`
+	DataSource string `json:"data_source"`
+	AuthToken  string `json:"auth_token"`
+}
+
+// openSessions holds the clients whose sessions are removed when the provider shuts down
+var openSessions = struct {
+	sync.Mutex
+	clients map[*guacamoleClient]bool
+}{
+	clients: make(map[*guacamoleClient]bool),
+}
+
+// connectGuacamole returns a client for a guacamole session.  Sessions created from a username and
+// password are removed by CloseSessions, unless sessionCachePath is set.  The session token is
+// then kept in that file and reused by later runs for as long as guacamole accepts it
+func connectGuacamole(config guac.Config, sessionCachePath string) (*guacamoleClient, error) {
+	if config.Token != "" || sessionCachePath == "" {
+		client, err := newGuacamoleClient(config)
+		if err != nil {
+			return nil, err
+		}
+		// sessions of supplied tokens belong to whoever created them
+		if config.Token == "" {
+			trackSession(client)
+		}
+		return client, nil
+	}
+
+	if cached, ok := readCachedSession(sessionCachePath, config); ok {
+		reuse := config
+		reuse.Token = cached.AuthToken
+		reuse.DataSource = cached.DataSource
+
+		// connecting with a token checks that guacamole still accepts it
+		client, err := newGuacamoleClient(reuse)
+		if err == nil {
+			client.config = config
+			client.sessionCachePath = sessionCachePath
+			return client, nil
+		}
+		log.Printf("[INFO] cached guacamole session in %s is no longer valid, authenticating again: %s", sessionCachePath, err)
+	}
+
+	client, err := newGuacamoleClient(config)
+	if err != nil {
+		return nil, err
+	}
+	client.sessionCachePath = sessionCachePath
+	client.cacheSession()
+
+	return client, nil
+}
+
+// cacheSession writes the session of a client to its session cache file.  Sessions that can't be
+// cached are removed by CloseSessions instead
+func (c *guacamoleClient) cacheSession() {
+	c.sessionLock.RLock()
+	session := cachedSession{
+		URL:        c.config.URL,
+		Username:   c.config.Username,
+		DataSource: c.session.DataSource,
+		AuthToken:  c.session.AuthToken,
+	}
+	c.sessionLock.RUnlock()
+
+	err := writeCachedSession(c.sessionCachePath, session)
+	if err != nil {
+		log.Printf("[WARN] unable to cache guacamole session in %s: %s", c.sessionCachePath, err)
+		trackSession(c)
+		return
+	}
+	untrackSession(c)
+}
+
+// readCachedSession reads the session cached for the url and username of a config
+func readCachedSession(path string, config guac.Config) (cachedSession, bool) {
+	var session cachedSession
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[WARN] unable to read guacamole session cache %s: %s", path, err)
+		}
+		return session, false
+	}
+	err = json.Unmarshal(raw, &session)
+	if err != nil {
+		log.Printf("[WARN] ignoring invalid guacamole session cache %s: %s", path, err)
+		return session, false
+	}
+
+	if session.URL != config.URL || session.Username != config.Username || session.AuthToken == "" {
+		return session, false
+	}
+
+	return session, true
+}
+
+// writeCachedSession replaces the content of a session cache file, readable by the current user
+// only
+func writeCachedSession(path string, session cachedSession) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(raw)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func trackSession(c *guacamoleClient) {
+	openSessions.Lock()
+	defer openSessions.Unlock()
+	openSessions.clients[c] = true
+}
+
+func untrackSession(c *guacamoleClient) {
+	openSessions.Lock()
+	defer openSessions.Unlock()
+	delete(openSessions.clients, c)
+}
+
+// closeSessionsTimeout bounds CloseSessions, terraform killing the provider 2 seconds after
+// asking it to shut down
+var closeSessionsTimeout = time.Second
+
+// CloseSessions removes the guacamole sessions opened by the provider that aren't kept in a
+// session cache file, giving up on those not removed within closeSessionsTimeout.  It is called
+// once the provider stops serving terraform
+func CloseSessions() {
+	openSessions.Lock()
+	var clients []*guacamoleClient
+	for c := range openSessions.clients {
+		clients = append(clients, c)
+		delete(openSessions.clients, c)
+	}
+	openSessions.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c *guacamoleClient) {
+			defer wg.Done()
+			err := c.Disconnect()
+			if err != nil {
+				log.Printf("[WARN] unable to remove guacamole session: %s", err)
+			}
+		}(c)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(closeSessionsTimeout):
+		log.Printf("[WARN] timed out removing guacamole sessions")
+	}
+}
@@ -0,0 +1,137 @@
+package guacamole
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	guac "github.com/techBeck03/guacamole-api-client"
+)
+
+func TestConnectGuacamoleSessionCache(t *testing.T) {
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+
+	path := filepath.Join(t.TempDir(), "sessions", "guacamole.json")
+	config := guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	}
+
+	first, err := connectGuacamole(config, path)
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected session cache to be written: %s", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected session cache to be readable by its owner only, got %s", info.Mode().Perm())
+	}
+
+	second, err := connectGuacamole(config, path)
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	if second.session.AuthToken != first.session.AuthToken || len(f.sessions) != 1 {
+		t.Errorf("expected cached session to be reused, got %d sessions", len(f.sessions))
+	}
+	if second.config.Token != "" {
+		t.Errorf("expected reused session to keep password authentication for reauthentication")
+	}
+	if _, err := second.ListUsers(); err != nil {
+		t.Errorf("unable to use reused session: %s", err)
+	}
+
+	// cached sessions outlive the provider
+	CloseSessions()
+	if len(f.sessions) != 1 {
+		t.Errorf("expected cached session to be kept on shutdown, got %d sessions", len(f.sessions))
+	}
+
+	// expired sessions are replaced
+	delete(f.sessions, first.session.AuthToken)
+	third, err := connectGuacamole(config, path)
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+	if third.session.AuthToken == first.session.AuthToken || len(f.sessions) != 1 {
+		t.Errorf("expected a new session to replace the expired one, got %d sessions", len(f.sessions))
+	}
+	cached, ok := readCachedSession(path, config)
+	if !ok || cached.AuthToken != third.session.AuthToken || cached.DataSource != fakeGuacamoleDataSource {
+		t.Errorf("expected session cache to hold the new session, got %#v", cached)
+	}
+
+	// sessions of other users or servers aren't reused
+	other := config
+	other.Username = "other"
+	if _, ok := readCachedSession(path, other); ok {
+		t.Errorf("expected session cache of %s not to be used for other", config.Username)
+	}
+}
+
+func TestCloseSessions(t *testing.T) {
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+
+	config := guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	}
+	if _, err := connectGuacamole(config, ""); err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+
+	// sessions of supplied tokens are left alone
+	token := guac.Config{
+		URL:        f.server.URL,
+		Token:      "supplied",
+		DataSource: fakeGuacamoleDataSource,
+	}
+	f.sessions["supplied"] = fakeGuacamoleUsername
+	if _, err := connectGuacamole(token, ""); err != nil {
+		t.Fatalf("unable to connect with token: %s", err)
+	}
+
+	if len(f.sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(f.sessions))
+	}
+	CloseSessions()
+	if _, ok := f.sessions["supplied"]; !ok || len(f.sessions) != 1 {
+		t.Errorf("expected only the supplied session to remain, got %v", f.sessions)
+	}
+}
+
+func TestCloseSessionsTimeout(t *testing.T) {
+	f := newFakeGuacamole()
+	t.Cleanup(f.close)
+
+	_, err := connectGuacamole(guac.Config{
+		URL:      f.server.URL,
+		Username: fakeGuacamoleUsername,
+		Password: fakeGuacamolePassword,
+	}, "")
+	if err != nil {
+		t.Fatalf("unable to connect: %s", err)
+	}
+
+	timeout := closeSessionsTimeout
+	closeSessionsTimeout = 50 * time.Millisecond
+	defer func() { closeSessionsTimeout = timeout }()
+
+	// a guacamole that doesn't answer doesn't hold up the provider shutting down
+	f.mu.Lock()
+	start := time.Now()
+	CloseSessions()
+	elapsed := time.Since(start)
+	f.mu.Unlock()
+
+	if elapsed > time.Second {
+		t.Fatalf("expected CloseSessions to give up after %s, took %s", closeSessionsTimeout, elapsed)
+	}
+}
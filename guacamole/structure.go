@@ -6,9 +6,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -31,6 +34,101 @@ func boolToString(b bool) string {
 	return ""
 }
 
+// stringToInt converts a guacamole string integer, treating an empty or invalid value as 0
+func stringToInt(v string) int {
+	if v == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(v)
+
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// intToString converts an integer to guacamole's string encoding, leaving 0 unset
+func intToString(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+// configuredIntToString converts an integer to guacamole's string encoding like intToString,
+// except that 0 is sent when it is configured.  Unset integers are read as 0 too, so only the
+// raw configuration tells them apart
+func configuredIntToString(i int, configured bool) string {
+	if i == 0 && configured {
+		return "0"
+	}
+	return intToString(i)
+}
+
+// configuredBlockValue returns the raw configuration of a field of a single nested block, such
+// as the attributes block, if it is configured
+func configuredBlockValue(config cty.Value, block string, field string) (cty.Value, bool) {
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(block) {
+		return cty.NilVal, false
+	}
+	list := config.GetAttr(block)
+	if list.IsNull() || !list.IsKnown() || list.LengthInt() == 0 {
+		return cty.NilVal, false
+	}
+	values := list.AsValueSlice()[0]
+	if values.IsNull() || !values.Type().HasAttribute(field) {
+		return cty.NilVal, false
+	}
+	value := values.GetAttr(field)
+	if value.IsNull() || !value.IsKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+// attributeConfigured returns whether a field of the attributes block is configured
+func attributeConfigured(config cty.Value, field string) bool {
+	_, ok := configuredBlockValue(config, "attributes", field)
+	return ok
+}
+
+// stringToList splits a guacamole comma separated value into a list
+func stringToList(v string) []string {
+	var list []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// listToString joins a list into guacamole's comma separated encoding
+func listToString(list []interface{}) string {
+	var values []string
+	for _, v := range list {
+		values = append(values, v.(string))
+	}
+	return strings.Join(values, ",")
+}
+
+// isZeroValue returns whether a value read from a resource is unset
+func isZeroValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if set, ok := value.(*schema.Set); ok {
+		return set.Len() == 0
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
 func sliceDiff(slice1 []string, slice2 []string, bidirectional bool) []string {
 	var diff []string
 
@@ -267,6 +365,52 @@ func validateTimestring(timeString string, name string) diag.Diagnostics {
 	return diags
 }
 
+// validatePOSIXRegex checks a value compiles as a POSIX extended regular expression, the syntax guacd uses
+func validatePOSIXRegex(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	_, err := regexp.CompilePOSIX(v.(string))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid regular expression",
+			Detail:        fmt.Sprintf("Expected a POSIX extended regular expression: %s", err),
+			AttributePath: path,
+		})
+	}
+	return diags
+}
+
+// validateStringOption returns a validator accepting an unset value or one of the valid options
+func validateStringOption(valid []string) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		if v.(string) == "" {
+			return nil
+		}
+		diags := stringInSlice(valid, []string{v.(string)})
+		for i := range diags {
+			diags[i].AttributePath = path
+		}
+		return diags
+	}
+}
+
+// validateTimezone checks a value is a timezone of the IANA time zone database
+func validateTimezone(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	_, err := time.LoadLocation(v.(string))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid timezone",
+			Detail:        fmt.Sprintf("Unable to process timezone string: %s", v.(string)),
+			AttributePath: path,
+		})
+	}
+	return diags
+}
+
 func testAccCheckTestSliceVals(resourceName string, key string, expected []string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -296,3 +440,17 @@ func testAccCheckTestSliceVals(resourceName string, key string, expected []strin
 		return nil
 	}
 }
+
+// suppressDefaultValueDiff returns a diff suppress function treating an unset value and
+// the value guacamole or guacd uses by default as equal.  Unset integers are stored as 0.
+func suppressDefaultValueDiff(defaultValue string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if old == "0" {
+			old = ""
+		}
+		if new == "0" {
+			new = ""
+		}
+		return (old == defaultValue && new == "") || (old == "" && new == defaultValue)
+	}
+}
@@ -0,0 +1,163 @@
+package guacamole
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Nested blocks whose integer and list values were stored as strings before schema version 1
+var typedStateBlocks = []string{"attributes", "parameters"}
+
+// stateFields describes the attributes of a schema version that is no longer current by type,
+// only to decode state written by it.  Nested blocks are lists of a single object, lists and
+// maps hold strings
+type stateFields map[string]interface{}
+
+// schema returns the schema of the described attributes
+func (f stateFields) schema() map[string]*schema.Schema {
+	s := make(map[string]*schema.Schema, len(f))
+	for k, v := range f {
+		switch v := v.(type) {
+		case stateFields:
+			s[k] = &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Resource{Schema: v.schema()},
+			}
+		case schema.ValueType:
+			s[k] = &schema.Schema{
+				Type:     v,
+				Optional: true,
+			}
+			if v == schema.TypeList || v == schema.TypeMap {
+				s[k].Elem = &schema.Schema{Type: schema.TypeString}
+			}
+		}
+	}
+	return s
+}
+
+// typedParametersStateUpgrader upgrades state written before integer and list parameters were
+// typed, when every attributes and parameters value was stored using guacamole's string encoding.
+// v0 describes that state
+func typedParametersStateUpgrader(r *schema.Resource, v0 stateFields) schema.StateUpgrader {
+	previous := &schema.Resource{
+		Schema: v0.schema(),
+	}
+
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    previous.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			return upgradeTypedParametersState(r.Schema, rawState), nil
+		},
+	}
+}
+
+// upgradeTypedParametersState converts the string encoded integers and comma separated lists of
+// the nested blocks into the types of the current schema, leaving values already typed unchanged
+func upgradeTypedParametersState(current map[string]*schema.Schema, rawState map[string]interface{}) map[string]interface{} {
+	for _, block := range typedStateBlocks {
+		s, ok := current[block]
+		if !ok {
+			continue
+		}
+		elem := s.Elem.(*schema.Resource)
+
+		list, _ := rawState[block].([]interface{})
+		for _, item := range list {
+			values, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for k, v := range values {
+				value, ok := v.(string)
+				if !ok {
+					continue
+				}
+				field, ok := elem.Schema[k]
+				if !ok {
+					continue
+				}
+				switch field.Type {
+				case schema.TypeInt:
+					values[k] = stringToInt(value)
+				case schema.TypeList:
+					var elements []interface{}
+					for _, element := range stringToList(value) {
+						elements = append(elements, element)
+					}
+					values[k] = elements
+				}
+			}
+		}
+	}
+
+	return rawState
+}
+
+// fontSizeStateUpgrader upgrades state of text protocol connections written before font_size was
+// typed, when it was stored using guacamole's string encoding.  v1 describes that state
+func fontSizeStateUpgrader(v1 stateFields) schema.StateUpgrader {
+	previous := &schema.Resource{
+		Schema: v1.schema(),
+	}
+
+	return schema.StateUpgrader{
+		Version: 1,
+		Type:    previous.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			return upgradeFontSizeStateV1(rawState), nil
+		},
+	}
+}
+
+// upgradeFontSizeStateV1 converts the string encoded font_size of the parameters block into an
+// integer
+func upgradeFontSizeStateV1(rawState map[string]interface{}) map[string]interface{} {
+	list, _ := rawState["parameters"].([]interface{})
+	for _, item := range list {
+		values, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := values["font_size"].(string); ok {
+			values["font_size"] = stringToInt(value)
+		}
+	}
+
+	return rawState
+}
+
+// parameterSectionsStateUpgrader upgrades state written before the parameters block was split
+// into section blocks.  The values are kept in the parameters block so that configurations still
+// using it plan no changes, they are moved into the section blocks by setConnectionParameters
+// once the configuration uses them.  Parameters typed since, such as the max_scrollback_size of
+// telnet connections, are converted
+func parameterSectionsStateUpgrader(r *schema.Resource, version int, sections []parameterSection) schema.StateUpgrader {
+	previous := &schema.Resource{
+		Schema: parameterSectionsSchemaPrevious(r.Schema, sections),
+	}
+
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    previous.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			return upgradeTypedParametersState(r.Schema, rawState), nil
+		},
+	}
+}
+
+// parameterSectionsSchemaPrevious returns a copy of a connection schema without the section blocks
+func parameterSectionsSchemaPrevious(current map[string]*schema.Schema, sections []parameterSection) map[string]*schema.Schema {
+	previous := make(map[string]*schema.Schema, len(current))
+	for k, v := range current {
+		previous[k] = v
+	}
+	for _, section := range sections {
+		delete(previous, section.name)
+	}
+
+	return previous
+}
@@ -0,0 +1,500 @@
+package guacamole
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// stateSchemasV0 describes the state of connection groups and connections by protocol as written
+// by schema version 0, when attributes and parameters values other than booleans were strings
+var stateSchemasV0 = map[string]stateFields{
+	"group": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"enable_session_affinity":  schema.TypeBool,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+		},
+		"identifier":        schema.TypeString,
+		"name":              schema.TypeString,
+		"parent_identifier": schema.TypeString,
+		"type":              schema.TypeString,
+	},
+	"ssh": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeString,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+			"weight":                   schema.TypeString,
+		},
+		"identifier": schema.TypeString,
+		"name":       schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"disable_copy":                schema.TypeBool,
+			"disable_paste":               schema.TypeBool,
+			"execute_command":             schema.TypeString,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"locale":                      schema.TypeString,
+			"max_scrollback_size":         schema.TypeString,
+			"passphrase":                  schema.TypeString,
+			"password":                    schema.TypeString,
+			"port":                        schema.TypeString,
+			"private_key":                 schema.TypeString,
+			"public_host_key":             schema.TypeString,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"server_keepalive":            schema.TypeString,
+			"sftp_disable_file_download":  schema.TypeBool,
+			"sftp_disable_file_upload":    schema.TypeBool,
+			"sftp_enable":                 schema.TypeBool,
+			"sftp_root_directory":         schema.TypeString,
+			"terminal_type":               schema.TypeString,
+			"timezone":                    schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"username":                    schema.TypeString,
+			"wol_boot_wait_time":          schema.TypeString,
+			"wol_broadcast_address":       schema.TypeString,
+			"wol_mac_address":             schema.TypeString,
+			"wol_send_packet":             schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+	},
+	"telnet": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeString,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+			"weight":                   schema.TypeString,
+		},
+		"identifier": schema.TypeString,
+		"name":       schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"disable_copy":                schema.TypeBool,
+			"disable_paste":               schema.TypeBool,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"login_failure_regex":         schema.TypeString,
+			"login_success_regex":         schema.TypeString,
+			"max_scrollback_size":         schema.TypeString,
+			"password":                    schema.TypeString,
+			"password_regex":              schema.TypeString,
+			"port":                        schema.TypeString,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"terminal_type":               schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"username":                    schema.TypeString,
+			"username_regex":              schema.TypeString,
+			"wol_boot_wait_time":          schema.TypeString,
+			"wol_broadcast_address":       schema.TypeString,
+			"wol_mac_address":             schema.TypeString,
+			"wol_send_packet":             schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+	},
+	"rdp": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeString,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+			"weight":                   schema.TypeString,
+		},
+		"identifier": schema.TypeString,
+		"name":       schema.TypeString,
+		"parameters": stateFields{
+			"administrator_console":        schema.TypeBool,
+			"client_name":                  schema.TypeString,
+			"color_depth":                  schema.TypeString,
+			"console_audio":                schema.TypeBool,
+			"create_drive_path":            schema.TypeBool,
+			"disable_audio":                schema.TypeBool,
+			"disable_authentication":       schema.TypeBool,
+			"disable_bitmap_caching":       schema.TypeBool,
+			"disable_copy":                 schema.TypeBool,
+			"disable_file_download":        schema.TypeBool,
+			"disable_file_upload":          schema.TypeBool,
+			"disable_glyph_caching":        schema.TypeBool,
+			"disable_offscreen_caching":    schema.TypeBool,
+			"disable_paste":                schema.TypeBool,
+			"domain":                       schema.TypeString,
+			"dpi":                          schema.TypeString,
+			"drive_name":                   schema.TypeString,
+			"drive_path":                   schema.TypeString,
+			"enable_audio_input":           schema.TypeBool,
+			"enable_desktop_composition":   schema.TypeBool,
+			"enable_drive":                 schema.TypeBool,
+			"enable_font_smoothing":        schema.TypeBool,
+			"enable_full_window_drag":      schema.TypeBool,
+			"enable_menu_animations":       schema.TypeBool,
+			"enable_printing":              schema.TypeBool,
+			"enable_theming":               schema.TypeBool,
+			"enable_wallpaper":             schema.TypeBool,
+			"gateway_domain":               schema.TypeString,
+			"gateway_hostname":             schema.TypeString,
+			"gateway_password":             schema.TypeString,
+			"gateway_port":                 schema.TypeString,
+			"gateway_username":             schema.TypeString,
+			"height":                       schema.TypeString,
+			"hostname":                     schema.TypeString,
+			"ignore_cert":                  schema.TypeBool,
+			"initial_program":              schema.TypeString,
+			"keyboard_layout":              schema.TypeString,
+			"load_balance_info":            schema.TypeString,
+			"password":                     schema.TypeString,
+			"port":                         schema.TypeString,
+			"preconnection_blob":           schema.TypeString,
+			"preconnection_id":             schema.TypeString,
+			"printer_name":                 schema.TypeString,
+			"readonly":                     schema.TypeBool,
+			"recording_auto_create_path":   schema.TypeBool,
+			"recording_exclude_mouse":      schema.TypeBool,
+			"recording_exclude_output":     schema.TypeBool,
+			"recording_include_keys":       schema.TypeBool,
+			"recording_name":               schema.TypeString,
+			"recording_path":               schema.TypeString,
+			"remote_app":                   schema.TypeString,
+			"remote_app_parameters":        schema.TypeString,
+			"remote_app_working_directory": schema.TypeString,
+			"resize_method":                schema.TypeString,
+			"security_mode":                schema.TypeString,
+			"sftp_disable_file_download":   schema.TypeBool,
+			"sftp_disable_file_upload":     schema.TypeBool,
+			"sftp_enable":                  schema.TypeBool,
+			"sftp_host_key":                schema.TypeString,
+			"sftp_hostname":                schema.TypeString,
+			"sftp_keepalive_interval":      schema.TypeString,
+			"sftp_passphrase":              schema.TypeString,
+			"sftp_password":                schema.TypeString,
+			"sftp_port":                    schema.TypeString,
+			"sftp_private_key":             schema.TypeString,
+			"sftp_root_directory":          schema.TypeString,
+			"sftp_upload_directory":        schema.TypeString,
+			"sftp_username":                schema.TypeString,
+			"static_channels":              schema.TypeString,
+			"timezone":                     schema.TypeString,
+			"username":                     schema.TypeString,
+			"width":                        schema.TypeString,
+			"wol_boot_wait_time":           schema.TypeString,
+			"wol_broadcast_address":        schema.TypeString,
+			"wol_mac_address":              schema.TypeString,
+			"wol_send_packet":              schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+	},
+	"vnc": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeString,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+			"weight":                   schema.TypeString,
+		},
+		"identifier": schema.TypeString,
+		"name":       schema.TypeString,
+		"parameters": stateFields{
+			"audio_server_name":          schema.TypeString,
+			"clipboard_encoding":         schema.TypeString,
+			"color_depth":                schema.TypeString,
+			"cursor":                     schema.TypeString,
+			"destination_host":           schema.TypeString,
+			"destination_port":           schema.TypeString,
+			"disable_copy":               schema.TypeBool,
+			"disable_paste":              schema.TypeBool,
+			"enable_audio":               schema.TypeBool,
+			"hostname":                   schema.TypeString,
+			"password":                   schema.TypeString,
+			"port":                       schema.TypeString,
+			"readonly":                   schema.TypeBool,
+			"recording_auto_create_path": schema.TypeBool,
+			"recording_exclude_mouse":    schema.TypeBool,
+			"recording_exclude_output":   schema.TypeBool,
+			"recording_include_keys":     schema.TypeBool,
+			"recording_name":             schema.TypeString,
+			"recording_path":             schema.TypeString,
+			"sftp_disable_file_download": schema.TypeBool,
+			"sftp_disable_file_upload":   schema.TypeBool,
+			"sftp_enable":                schema.TypeBool,
+			"sftp_host_key":              schema.TypeString,
+			"sftp_hostname":              schema.TypeString,
+			"sftp_keepalive_interval":    schema.TypeString,
+			"sftp_passphrase":            schema.TypeString,
+			"sftp_password":              schema.TypeString,
+			"sftp_port":                  schema.TypeString,
+			"sftp_private_key":           schema.TypeString,
+			"sftp_root_directory":        schema.TypeString,
+			"sftp_upload_directory":      schema.TypeString,
+			"sftp_username":              schema.TypeString,
+			"swap_red_blue":              schema.TypeBool,
+			"username":                   schema.TypeString,
+			"wol_boot_wait_time":         schema.TypeString,
+			"wol_broadcast_address":      schema.TypeString,
+			"wol_mac_address":            schema.TypeString,
+			"wol_send_packet":            schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+	},
+	"kubernetes": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeString,
+			"max_connections":          schema.TypeString,
+			"max_connections_per_user": schema.TypeString,
+			"weight":                   schema.TypeString,
+		},
+		"identifier": schema.TypeString,
+		"name":       schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"ca_cert":                     schema.TypeString,
+			"client_cert":                 schema.TypeString,
+			"client_key":                  schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"container":                   schema.TypeString,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"ignore_cert":                 schema.TypeBool,
+			"max_scrollback_size":         schema.TypeString,
+			"namespace":                   schema.TypeString,
+			"pod":                         schema.TypeString,
+			"port":                        schema.TypeString,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"use_ssl":                     schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+	},
+}
+
+// textStateSchemasV1 describes the state of text protocol connections as written by schema
+// version 1, when font_size was still a string
+var textStateSchemasV1 = map[string]stateFields{
+	"ssh": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeInt,
+			"max_connections":          schema.TypeInt,
+			"max_connections_per_user": schema.TypeInt,
+			"weight":                   schema.TypeInt,
+		},
+		"extra_attributes": schema.TypeMap,
+		"identifier":       schema.TypeString,
+		"name":             schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"clipboard_encoding":          schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"disable_copy":                schema.TypeBool,
+			"disable_paste":               schema.TypeBool,
+			"disable_server_input":        schema.TypeBool,
+			"execute_command":             schema.TypeString,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"locale":                      schema.TypeString,
+			"max_scrollback_size":         schema.TypeInt,
+			"passphrase":                  schema.TypeString,
+			"password":                    schema.TypeString,
+			"port":                        schema.TypeInt,
+			"private_key":                 schema.TypeString,
+			"public_host_key":             schema.TypeString,
+			"public_key":                  schema.TypeString,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_exclude_touch":     schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"recording_write_existing":    schema.TypeBool,
+			"server_keepalive":            schema.TypeInt,
+			"sftp_disable_file_download":  schema.TypeBool,
+			"sftp_disable_file_upload":    schema.TypeBool,
+			"sftp_enable":                 schema.TypeBool,
+			"sftp_host_key":               schema.TypeString,
+			"sftp_hostname":               schema.TypeString,
+			"sftp_keepalive_interval":     schema.TypeInt,
+			"sftp_passphrase":             schema.TypeString,
+			"sftp_password":               schema.TypeString,
+			"sftp_port":                   schema.TypeInt,
+			"sftp_private_key":            schema.TypeString,
+			"sftp_root_directory":         schema.TypeString,
+			"sftp_upload_directory":       schema.TypeString,
+			"sftp_username":               schema.TypeString,
+			"terminal_type":               schema.TypeString,
+			"timezone":                    schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"username":                    schema.TypeString,
+			"wol_boot_wait_time":          schema.TypeInt,
+			"wol_broadcast_address":       schema.TypeString,
+			"wol_mac_address":             schema.TypeString,
+			"wol_send_packet":             schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+		"secret_state_mode": schema.TypeString,
+	},
+	"telnet": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeInt,
+			"max_connections":          schema.TypeInt,
+			"max_connections_per_user": schema.TypeInt,
+			"weight":                   schema.TypeInt,
+		},
+		"extra_attributes": schema.TypeMap,
+		"identifier":       schema.TypeString,
+		"name":             schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"clipboard_encoding":          schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"disable_copy":                schema.TypeBool,
+			"disable_paste":               schema.TypeBool,
+			"disable_server_input":        schema.TypeBool,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"locale":                      schema.TypeString,
+			"login_failure_regex":         schema.TypeString,
+			"login_success_regex":         schema.TypeString,
+			"max_scrollback_size":         schema.TypeString,
+			"password":                    schema.TypeString,
+			"password_regex":              schema.TypeString,
+			"port":                        schema.TypeInt,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_exclude_touch":     schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"terminal_type":               schema.TypeString,
+			"timezone":                    schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"username":                    schema.TypeString,
+			"username_regex":              schema.TypeString,
+			"wol_boot_wait_time":          schema.TypeInt,
+			"wol_broadcast_address":       schema.TypeString,
+			"wol_mac_address":             schema.TypeString,
+			"wol_send_packet":             schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+		"secret_state_mode": schema.TypeString,
+	},
+	"kubernetes": {
+		"active_connections": schema.TypeInt,
+		"attributes": stateFields{
+			"failover_only":            schema.TypeBool,
+			"guacd_encryption":         schema.TypeString,
+			"guacd_hostname":           schema.TypeString,
+			"guacd_port":               schema.TypeInt,
+			"max_connections":          schema.TypeInt,
+			"max_connections_per_user": schema.TypeInt,
+			"weight":                   schema.TypeInt,
+		},
+		"extra_attributes": schema.TypeMap,
+		"identifier":       schema.TypeString,
+		"name":             schema.TypeString,
+		"parameters": stateFields{
+			"backspace":                   schema.TypeString,
+			"ca_cert":                     schema.TypeString,
+			"client_cert":                 schema.TypeString,
+			"client_key":                  schema.TypeString,
+			"clipboard_encoding":          schema.TypeString,
+			"color_scheme":                schema.TypeString,
+			"container":                   schema.TypeString,
+			"disable_copy":                schema.TypeBool,
+			"disable_paste":               schema.TypeBool,
+			"exec_command":                schema.TypeString,
+			"font_name":                   schema.TypeString,
+			"font_size":                   schema.TypeString,
+			"hostname":                    schema.TypeString,
+			"ignore_cert":                 schema.TypeBool,
+			"max_scrollback_size":         schema.TypeInt,
+			"namespace":                   schema.TypeString,
+			"pod":                         schema.TypeString,
+			"port":                        schema.TypeInt,
+			"readonly":                    schema.TypeBool,
+			"recording_auto_create_path":  schema.TypeBool,
+			"recording_exclude_mouse":     schema.TypeBool,
+			"recording_exclude_output":    schema.TypeBool,
+			"recording_exclude_touch":     schema.TypeBool,
+			"recording_include_keys":      schema.TypeBool,
+			"recording_name":              schema.TypeString,
+			"recording_path":              schema.TypeString,
+			"terminal_type":               schema.TypeString,
+			"typescript_auto_create_path": schema.TypeBool,
+			"typescript_name":             schema.TypeString,
+			"typescript_path":             schema.TypeString,
+			"use_ssl":                     schema.TypeBool,
+		},
+		"parent_identifier": schema.TypeString,
+		"protocol":          schema.TypeString,
+		"secret_state_mode": schema.TypeString,
+	},
+}
@@ -0,0 +1,286 @@
+package guacamole
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestTypedParametersStateUpgraderRDP(t *testing.T) {
+	r := guacamoleConnectionRDP()
+
+	rawState := map[string]interface{}{
+		"name":     "rdp",
+		"protocol": "rdp",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"guacd_hostname": "guacd.example.com",
+				"guacd_port":     "4822",
+				"weight":         "",
+			},
+		},
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":        "testing.example.com",
+				"port":            "3389",
+				"width":           "2560",
+				"static_channels": "channel-1, channel-2",
+			},
+		},
+	}
+
+	upgraded, err := r.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error upgrading state: %s", err)
+	}
+
+	attributes := upgraded["attributes"].([]interface{})[0].(map[string]interface{})
+	if attributes["guacd_port"] != 4822 {
+		t.Fatalf("expected guacd_port to be upgraded to an integer, got %#v", attributes["guacd_port"])
+	}
+	if attributes["weight"] != 0 {
+		t.Fatalf("expected empty weight to be upgraded to 0, got %#v", attributes["weight"])
+	}
+	if attributes["guacd_hostname"] != "guacd.example.com" {
+		t.Fatalf("expected string attributes to be kept, got %#v", attributes["guacd_hostname"])
+	}
+
+	parameters := upgraded["parameters"].([]interface{})[0].(map[string]interface{})
+	if parameters["port"] != 3389 || parameters["width"] != 2560 {
+		t.Fatalf("expected integer parameters to be upgraded, got %#v and %#v", parameters["port"], parameters["width"])
+	}
+	expected := []interface{}{"channel-1", "channel-2"}
+	if !reflect.DeepEqual(parameters["static_channels"], expected) {
+		t.Fatalf("expected static_channels to be upgraded to %v, got %#v", expected, parameters["static_channels"])
+	}
+}
+
+func TestStateSchemasV0(t *testing.T) {
+	attributes := stateSchemasV0["group"].schema()["attributes"].Elem.(*schema.Resource).Schema
+	if attributes["max_connections"].Type != schema.TypeString {
+		t.Fatalf("expected max_connections to be a string in version 0")
+	}
+	if attributes["enable_session_affinity"].Type != schema.TypeBool {
+		t.Fatalf("expected enable_session_affinity to be a boolean in version 0")
+	}
+
+	for name, v0 := range stateSchemasV0 {
+		for _, k := range []string{"extra_attributes", "secret_state_mode"} {
+			if _, ok := v0[k]; ok {
+				t.Fatalf("expected %s to be missing from version 0 of %s", k, name)
+			}
+		}
+	}
+}
+
+func TestConvertGuacConnectionRDPTypedParameters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, guacamoleConnectionRDP().Schema, map[string]interface{}{
+		"name": "rdp",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":        "testing.example.com",
+				"port":            3390,
+				"static_channels": []interface{}{"channel-1", "channel-2"},
+			},
+		},
+	})
+
+	connection, _, diags := convertResourceDataToGuacConnection(d, "rdp")
+	if diags.HasError() {
+		t.Fatalf("unexpected error converting connection: %v", diags)
+	}
+	if connection.Parameters.Port != "3390" {
+		t.Fatalf("expected port to be sent as a string, got %q", connection.Parameters.Port)
+	}
+	if connection.Parameters.Width != "" {
+		t.Fatalf("expected unset width to be omitted, got %q", connection.Parameters.Width)
+	}
+	if connection.Parameters.StaticChannels != "channel-1,channel-2" {
+		t.Fatalf("expected static_channels to be joined, got %q", connection.Parameters.StaticChannels)
+	}
+}
+
+func TestFontSizeStateUpgrader(t *testing.T) {
+	r := guacamoleConnectionTelnet()
+
+	rawState := map[string]interface{}{
+		"name":     "telnet",
+		"protocol": "telnet",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":     "testing.example.com",
+				"port":         23,
+				"color_scheme": "green-black",
+				"font_size":    "12",
+			},
+		},
+	}
+
+	upgraded, err := r.StateUpgraders[1].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error upgrading state: %s", err)
+	}
+
+	parameters := upgraded["parameters"].([]interface{})[0].(map[string]interface{})
+	if parameters["font_size"] != 12 {
+		t.Fatalf("expected font_size to be upgraded to an integer, got %#v", parameters["font_size"])
+	}
+	if parameters["port"] != 23 || parameters["color_scheme"] != "green-black" {
+		t.Fatalf("expected other parameters to be kept, got %#v", parameters)
+	}
+
+	v1 := textStateSchemasV1["telnet"].schema()["parameters"].Elem.(*schema.Resource).Schema
+	if v1["font_size"].Type != schema.TypeString {
+		t.Fatalf("expected font_size to be a string in version 1")
+	}
+	if _, ok := v1["custom_color_scheme"]; ok {
+		t.Fatalf("expected custom_color_scheme to be missing from version 1")
+	}
+}
+
+func TestTelnetScrollbackStateUpgrader(t *testing.T) {
+	r := guacamoleConnectionTelnet()
+
+	rawState := map[string]interface{}{
+		"name":     "telnet",
+		"protocol": "telnet",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":            "testing.example.com",
+				"font_size":           "12",
+				"max_scrollback_size": "2048",
+			},
+		},
+	}
+
+	for _, upgrader := range r.StateUpgraders[1:] {
+		var err error
+		rawState, err = upgrader.Upgrade(context.Background(), rawState, nil)
+		if err != nil {
+			t.Fatalf("unexpected error upgrading state from version %d: %s", upgrader.Version, err)
+		}
+	}
+
+	parameters := rawState["parameters"].([]interface{})[0].(map[string]interface{})
+	if parameters["max_scrollback_size"] != 2048 || parameters["font_size"] != 12 {
+		t.Fatalf("expected max_scrollback_size and font_size to be upgraded to integers, got %#v", parameters)
+	}
+}
+
+func TestParameterSectionsStateUpgrader(t *testing.T) {
+	r := guacamoleConnectionSSH()
+
+	rawState := map[string]interface{}{
+		"name":     "ssh",
+		"protocol": "ssh",
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":    "testing.example.com",
+				"port":        22,
+				"username":    "user",
+				"password":    "hunter2",
+				"sftp_enable": true,
+			},
+		},
+	}
+
+	upgraded, err := r.StateUpgraders[2].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error upgrading state: %s", err)
+	}
+
+	parameters := upgraded["parameters"].([]interface{})[0].(map[string]interface{})
+	if parameters["hostname"] != "testing.example.com" || parameters["password"] != "hunter2" {
+		t.Fatalf("expected the parameters block to be kept, got %#v", parameters)
+	}
+	for _, section := range []string{"network", "authentication", "sftp"} {
+		if _, ok := upgraded[section]; ok {
+			t.Fatalf("expected section %s to be left out of upgraded state", section)
+		}
+	}
+
+	previous := parameterSectionsSchemaPrevious(r.Schema, connectionParameterSections("ssh"))
+	if _, ok := previous["network"]; ok {
+		t.Fatalf("expected section blocks to be missing from the previous schema")
+	}
+}
+
+func TestParameterSectionsStateUpgraderNoChanges(t *testing.T) {
+	_, client := newTestFakeGuacamole(t)
+	r := guacamoleConnectionSSH()
+
+	config := map[string]interface{}{
+		"name": "fakeConnectionSSH",
+		"attributes": []interface{}{
+			map[string]interface{}{
+				"max_connections": 2,
+			},
+		},
+		"parameters": []interface{}{
+			map[string]interface{}{
+				"hostname":  "hostname.example.com",
+				"port":      2222,
+				"username":  "user",
+				"password":  "password",
+				"font_size": 13,
+			},
+		},
+	}
+	state := testFakeApply(t, r, nil, config, client)
+
+	// state written before the section blocks has the same layout, without the section blocks
+	coreSchema := r.CoreConfigSchema()
+	value, err := state.AttrsAsObjectValue(coreSchema.ImpliedType())
+	if err != nil {
+		t.Fatalf("unable to decode state: %s", err)
+	}
+	rawState, err := schema.StateValueToJSONMap(value, coreSchema.ImpliedType())
+	if err != nil {
+		t.Fatalf("unable to encode state: %s", err)
+	}
+	for _, section := range connectionParameterSections("ssh") {
+		delete(rawState, section.name)
+	}
+
+	upgraded, err := r.StateUpgraders[2].Upgrade(context.Background(), rawState, client)
+	if err != nil {
+		t.Fatalf("unexpected error upgrading state: %s", err)
+	}
+	value, err = schema.JSONMapToStateValue(upgraded, coreSchema)
+	if err != nil {
+		t.Fatalf("unable to decode upgraded state: %s", err)
+	}
+	upgradedState, err := r.ShimInstanceStateFromValue(value)
+	if err != nil {
+		t.Fatalf("unable to shim upgraded state: %s", err)
+	}
+
+	testFakeCheckNoChanges(t, r, upgradedState, config, client)
+
+	// moving the configuration to the section blocks moves the values in state
+	delete(config, "parameters")
+	config["network"] = []interface{}{
+		map[string]interface{}{
+			"hostname": "hostname.example.com",
+			"port":     2222,
+		},
+	}
+	config["authentication"] = []interface{}{
+		map[string]interface{}{
+			"username": "user",
+			"password": "password",
+		},
+	}
+	config["display"] = []interface{}{
+		map[string]interface{}{
+			"font_size": 13,
+		},
+	}
+	state = testFakeApply(t, r, upgradedState, config, client)
+
+	if state.Attributes["parameters.#"] != "0" || state.Attributes["network.0.hostname"] != "hostname.example.com" {
+		t.Fatalf("expected the values to be moved into the section blocks, got %v", state.Attributes)
+	}
+}
@@ -1,15 +1,59 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	guac "github.com/techBeck03/guacamole-api-client"
 	"github.com/techBeck03/terraform-provider-guacamole/guacamole"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		err := export(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export failed: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	guacamole.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() *schema.Provider {
 			return guacamole.Provider()
 		},
 	})
 }
+
+// export generates terraform configuration and import blocks from an existing guacamole instance
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	url := flags.String("url", os.Getenv("GUACAMOLE_URL"), "URL of guacamole web server")
+	username := flags.String("username", os.Getenv("GUACAMOLE_USERNAME"), "Username to authenticate to guacamole")
+	password := flags.String("password", os.Getenv("GUACAMOLE_PASSWORD"), "Password to authenticate to guacamole")
+	token := flags.String("token", os.Getenv("GUACAMOLE_TOKEN"), "Token to authenticate to guacamole")
+	dataSource := flags.String("data-source", os.Getenv("GUACAMOLE_DATA_SOURCE"), "Data source for token based authentication")
+	disableTLS := flags.Bool("disable-tls-verification", os.Getenv("GUACAMOLE_DISABLE_TLS") == "true", "Disable tls verification")
+	out := flags.String("out", ".", "Directory to write the generated configuration to")
+	flags.Parse(args)
+
+	if *url == "" {
+		return fmt.Errorf("--url must be specified")
+	}
+	if *password == "" && *token == "" {
+		return fmt.Errorf("either --username/--password or --token/--data-source must be specified")
+	}
+
+	return guacamole.Export(guac.Config{
+		URL:                    strings.TrimRight(*url, "/"),
+		Username:               *username,
+		Password:               *password,
+		Token:                  *token,
+		DataSource:             *dataSource,
+		DisableTLSVerification: *disableTLS,
+	}, *out)
+}